@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/smart-cs/scheduler-backend/schedules"
+)
+
+// autocomplete prints the courses completing the arguments like GET /autocomplete, one per line.
+// Returns 1 if there are none.
+func autocomplete(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("autocomplete", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	catalog := flags.String("catalog", defaultDatabasePath, "path of the course database")
+	limit := flags.Int("limit", 10, "most courses to print, 0 for all of them")
+	asJSON := flags.Bool("json", false, "print the completions as JSON")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: autocomplete [-catalog path] [-limit n] [-json] text")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	text := strings.Join(flags.Args(), " ")
+	if text == "" || *limit < 0 {
+		flags.Usage()
+		return 2
+	}
+	if !loadCatalog(*catalog, stderr) {
+		return 2
+	}
+
+	ac, err := schedules.NewAutoCompleter()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	completions := ac.Complete(text, *limit)
+	if *asJSON {
+		if err := writeJSON(stdout, completions); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	} else {
+		for _, c := range completions {
+			fmt.Fprintln(stdout, c.Code)
+		}
+	}
+	if len(completions) == 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/smart-cs/scheduler-backend/schedules"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutocomplete(t *testing.T) {
+	assert := assert.New(t)
+	var stdout, stderr bytes.Buffer
+
+	assert.Equal(0, autocomplete([]string{"-catalog", testCatalog, "-limit", "3", "cpsc", "2"}, &stdout, &stderr))
+	assert.Equal("CPSC 210\nCPSC 213\nCPSC 221\n", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(0, autocomplete([]string{"-catalog", testCatalog, "-json", "CSPC 221"}, &stdout, &stderr))
+	var completions []schedules.Completion
+	assert.NoError(json.Unmarshal(stdout.Bytes(), &completions))
+	assert.Equal("CPSC 221", completions[0].Code)
+
+	stdout.Reset()
+	assert.Equal(1, autocomplete([]string{"-catalog", testCatalog, "ZZZZZZZZZZZZ"}, &stdout, &stderr))
+	assert.Empty(stdout.String())
+	assert.Equal(2, autocomplete([]string{"-catalog", testCatalog}, &stdout, &stderr))
+}
@@ -0,0 +1,293 @@
+package database
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/smart-cs/scheduler-backend/models"
+)
+
+// Catalog is a typed, pre-parsed view of a CourseDatabase.
+// It is built once when the database is loaded so lookups don't need to re-parse sections.
+// A Catalog never changes once it's built, the With methods return a copy sharing the parsed courses.
+type Catalog struct {
+	departments map[string]*CatalogDepartment
+	courses     map[string]*CatalogCourse
+	sections    map[string]*CatalogSection
+	links       SectionLinks
+	metadata    CourseMetadata
+	credits     CourseCredits
+}
+
+// CatalogDepartment is a department in the catalog. e.g. 'CPSC'
+type CatalogDepartment struct {
+	Name string
+	// Courses of the department sorted by name.
+	Courses []*CatalogCourse
+}
+
+// CatalogCourse is a course in the catalog. e.g. 'CPSC 121'
+type CatalogCourse struct {
+	Name       string
+	Department string
+	// Sections of the course sorted by name.
+	Sections []*CatalogSection
+
+	byActivity map[string][]*CatalogSection
+}
+
+// CatalogSection is a section of a course in the catalog. e.g. 'CPSC 121 101'
+type CatalogSection struct {
+	Name   string
+	Course string
+	// Activity of the first meeting. e.g. 'Lecture'
+	Activity string
+	// Term of the first meeting. e.g. '1'
+	Term     string
+	Status   models.SectionStatus
+	Interval string
+	Meetings []CatalogMeeting
+}
+
+// CatalogMeeting is a single row of a section's schedule.
+type CatalogMeeting struct {
+	Activity string
+	// Days the meeting happens. e.g. ['Mon', 'Wed', 'Fri']
+	Days []string
+	// Start time of the meeting (24 hour representation). e.g. 1230
+	Start int
+	// End time of the meeting (24 hour representation). e.g. 1530
+	End int
+	// Timed is false if the start or end time is missing or malformed.
+	Timed bool
+	Term  string
+}
+
+// NewCatalog builds a Catalog from a CourseDatabase.
+func NewCatalog(db CourseDatabase) *Catalog {
+	c := &Catalog{
+		departments: make(map[string]*CatalogDepartment, len(db)),
+		courses:     make(map[string]*CatalogCourse),
+		sections:    make(map[string]*CatalogSection),
+	}
+	for deptName, courses := range db {
+		dept := &CatalogDepartment{Name: deptName}
+		for courseName, sections := range courses {
+			course := &CatalogCourse{
+				Name:       courseName,
+				Department: deptName,
+				byActivity: make(map[string][]*CatalogSection),
+			}
+			for sectionName, s := range sections {
+				if !strings.HasPrefix(sectionName, courseName) {
+					continue
+				}
+				section := newCatalogSection(sectionName, courseName, s)
+				course.Sections = append(course.Sections, section)
+				c.sections[sectionName] = section
+			}
+			sort.Slice(course.Sections, func(i, j int) bool {
+				return course.Sections[i].Name < course.Sections[j].Name
+			})
+			for _, s := range course.Sections {
+				course.byActivity[s.Activity] = append(course.byActivity[s.Activity], s)
+			}
+			dept.Courses = append(dept.Courses, course)
+			c.courses[courseName] = course
+		}
+		sort.Slice(dept.Courses, func(i, j int) bool {
+			return dept.Courses[i].Name < dept.Courses[j].Name
+		})
+		c.departments[deptName] = dept
+	}
+	return c
+}
+
+func newCatalogSection(name, course string, s Section) *CatalogSection {
+	section := &CatalogSection{
+		Name:     name,
+		Course:   course,
+		Status:   models.ParseSectionStatus(s.Status),
+		Interval: s.Interval,
+	}
+	for i := range s.Activity {
+		m := CatalogMeeting{
+			Activity: s.Activity[i],
+			Days:     strings.Fields(field(s.Days, i)),
+			Term:     field(s.Term, i),
+		}
+		start, startErr := models.ParseTime(field(s.StartTime, i))
+		end, endErr := models.ParseTime(field(s.EndTime, i))
+		if startErr == nil && endErr == nil {
+			m.Start, m.End, m.Timed = start, end, true
+		}
+		section.Meetings = append(section.Meetings, m)
+	}
+	if len(section.Meetings) > 0 {
+		section.Activity = section.Meetings[0].Activity
+		section.Term = section.Meetings[0].Term
+	}
+	return section
+}
+
+// field returns the i-th element of a section column or "" if the column is too short.
+func field(column []string, i int) string {
+	if i < len(column) {
+		return column[i]
+	}
+	return ""
+}
+
+// WithSectionLinks returns a copy of the catalog with the explicit links between its sections.
+func (c *Catalog) WithSectionLinks(links SectionLinks) *Catalog {
+	copied := *c
+	copied.links = links
+	return &copied
+}
+
+// WithCourseMetadata returns a copy of the catalog with the titles, credits, descriptions and prerequisites of its courses.
+func (c *Catalog) WithCourseMetadata(metadata CourseMetadata) *Catalog {
+	copied := *c
+	copied.metadata = metadata
+	return &copied
+}
+
+// WithCourseCredits returns a copy of the catalog with the credits of its courses, overriding the credits in the course metadata.
+func (c *Catalog) WithCourseCredits(credits CourseCredits) *Catalog {
+	copied := *c
+	copied.credits = credits
+	return &copied
+}
+
+// CourseInfo returns the information about a course, with only its code, department, number
+// and credits if it has no metadata. Returns false if the course doesn't exist.
+func (c *Catalog) CourseInfo(name string) (models.Course, bool) {
+	course := c.Course(name)
+	if course == nil {
+		return models.Course{}, false
+	}
+	info := c.metadata[name]
+	info.Code = course.Name
+	info.Department = course.Department
+	info.Number = strings.TrimPrefix(course.Name, course.Department+" ")
+	if credits, ok := c.credits[name]; ok {
+		info.Credits = credits
+	}
+	return info, true
+}
+
+// Linked returns true if a companion section (e.g. a Laboratory) can be taken with a primary section (e.g. a Lecture).
+// An explicit link of the companion decides, otherwise they must share a term. The term of a section without one
+// comes from the section code convention: companions coded like 'L1A' or 'T2B' belong to primary sections coded like '101' or '201'.
+func (c *Catalog) Linked(primary, companion string) bool {
+	p, comp := c.Section(primary), c.Section(companion)
+	if p == nil || comp == nil {
+		return false
+	}
+	if primaries, ok := c.links[companion]; ok {
+		return models.ContainsName(primaries, primary)
+	}
+	primaryTerms, companionTerms := p.Terms(), comp.Terms()
+	if primaryTerms == 0 {
+		primaryTerms = codeTerms(primaryCodeTerm(p.Code()))
+	}
+	if companionTerms == 0 {
+		companionTerms = codeTerms(companionCodeTerm(comp.Code()))
+	}
+	return primaryTerms == 0 || companionTerms == 0 || primaryTerms&companionTerms != 0
+}
+
+// primaryCodeTerm returns the term digit of a primary section code like '101', 0 if there is none.
+func primaryCodeTerm(code string) byte {
+	if len(code) == 3 && isDigit(code[0]) && isDigit(code[1]) && isDigit(code[2]) && isTermDigit(code[0]) {
+		return code[0]
+	}
+	return 0
+}
+
+// companionCodeTerm returns the term digit of a companion section code like 'L1A', 0 if there is none.
+func companionCodeTerm(code string) byte {
+	if len(code) == 3 && !isDigit(code[0]) && isTermDigit(code[1]) {
+		return code[1]
+	}
+	return 0
+}
+
+// codeTerms returns the terms of a term digit of a section code, none if there is no digit.
+func codeTerms(digit byte) models.TermSet {
+	if digit == 0 {
+		return 0
+	}
+	return models.ParseTermSet(string(digit))
+}
+
+func isDigit(b byte) bool {
+	return '0' <= b && b <= '9'
+}
+
+func isTermDigit(b byte) bool {
+	return b == '1' || b == '2'
+}
+
+// Code returns the code of the section without the course. e.g. 'L1A' for 'CPSC 121 L1A'
+func (s *CatalogSection) Code() string {
+	return strings.TrimPrefix(s.Name, s.Course+" ")
+}
+
+// Terms returns the set of terms of all meetings of the section.
+func (s *CatalogSection) Terms() models.TermSet {
+	var terms models.TermSet
+	for _, m := range s.Meetings {
+		terms |= models.ParseTermSet(m.Term)
+	}
+	return terms
+}
+
+// Departments returns all departments sorted by name.
+func (c *Catalog) Departments() []*CatalogDepartment {
+	depts := make([]*CatalogDepartment, 0, len(c.departments))
+	for _, d := range c.departments {
+		depts = append(depts, d)
+	}
+	sort.Slice(depts, func(i, j int) bool { return depts[i].Name < depts[j].Name })
+	return depts
+}
+
+// Department returns the department with the given name or nil if it doesn't exist.
+func (c *Catalog) Department(name string) *CatalogDepartment {
+	return c.departments[name]
+}
+
+// Course returns the course with the given name or nil if it doesn't exist.
+func (c *Catalog) Course(name string) *CatalogCourse {
+	return c.courses[name]
+}
+
+// Section returns the section with the given name or nil if it doesn't exist.
+func (c *Catalog) Section(name string) *CatalogSection {
+	return c.sections[name]
+}
+
+// CourseNames returns the names of all courses sorted.
+func (c *Catalog) CourseNames() []string {
+	names := make([]string, 0, len(c.courses))
+	for name := range c.courses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Terms returns the set of terms of all sections of the course.
+func (c *CatalogCourse) Terms() models.TermSet {
+	var terms models.TermSet
+	for _, s := range c.Sections {
+		terms |= s.Terms()
+	}
+	return terms
+}
+
+// SectionsWithActivity returns the sections whose activity is the given activity, sorted by name.
+func (c *CatalogCourse) SectionsWithActivity(activity string) []*CatalogSection {
+	return c.byActivity[activity]
+}
@@ -0,0 +1,179 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/smart-cs/scheduler-backend/database"
+	"github.com/smart-cs/scheduler-backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCatalog(t *testing.T) {
+	assert := assert.New(t)
+	db := database.CourseDatabase{
+		"CPSC": {
+			"CPSC 121": {
+				"CPSC 121 L1A": {
+					Activity:  []string{"Laboratory"},
+					Days:      []string{"Wed"},
+					StartTime: []string{"16:00"},
+					EndTime:   []string{"18:00"},
+					Term:      []string{"1"},
+				},
+				"CPSC 121 101": {
+					Activity:  []string{"Lecture", "Lecture"},
+					Days:      []string{"Tue Thu", "Fri"},
+					StartTime: []string{"11:00", "9:00"},
+					EndTime:   []string{"12:30", ""},
+					Term:      []string{"1", "1"},
+					Status:    "Full",
+				},
+			},
+		},
+	}
+	c := database.NewCatalog(db)
+
+	assert.Equal([]string{"CPSC 121"}, c.CourseNames())
+	assert.Nil(c.Course("bogus"))
+	assert.Nil(c.Department("bogus"))
+	assert.Nil(c.Section("bogus"))
+	assert.Len(c.Departments(), 1)
+	assert.Len(c.Department("CPSC").Courses, 1)
+
+	course := c.Course("CPSC 121")
+	assert.Equal("CPSC", course.Department)
+	assert.Len(course.Sections, 2)
+	assert.Equal("CPSC 121 101", course.Sections[0].Name, "sections should be sorted by name")
+	assert.Len(course.SectionsWithActivity("Lecture"), 1)
+	assert.Len(course.SectionsWithActivity("Laboratory"), 1)
+	assert.Empty(course.SectionsWithActivity("Tutorial"))
+
+	section := c.Section("CPSC 121 101")
+	assert.Equal("Lecture", section.Activity)
+	assert.Equal("1", section.Term)
+	assert.Equal(models.Full, section.Status)
+	assert.Equal([]database.CatalogMeeting{
+		{Activity: "Lecture", Days: []string{"Tue", "Thu"}, Start: 1100, End: 1230, Timed: true, Term: "1"},
+		{Activity: "Lecture", Days: []string{"Fri"}, Start: 0, End: 0, Timed: false, Term: "1"},
+	}, section.Meetings)
+}
+
+func TestCourseCatalog(t *testing.T) {
+	assert := assert.New(t)
+	database.LoadLocalDatabase("test-coursedb.json")
+	c := currentSnapshot(t).Catalog
+
+	validCourses, err := database.ValidCourses()
+	assert.NoError(err)
+	assert.Equal(validCourses, c.CourseNames())
+	assert.NotNil(c.Course("CPSC 121"))
+	assert.NotNil(c.Section("CPSC 121 101"))
+	assert.Len(c.Course("CPSC 110").SectionsWithActivity("Lecture"), 8)
+	assert.Equal(models.Term1|models.Term2, c.Course("CPSC 121").Terms())
+	assert.Equal(models.Term1, c.Course("APSC 100").Terms())
+}
+
+func TestCatalogCourseInfo(t *testing.T) {
+	assert := assert.New(t)
+	database.LoadLocalDatabase("test-coursedb.json")
+	c := currentSnapshot(t).Catalog
+
+	info, ok := c.CourseInfo("CPSC 221")
+	assert.True(ok)
+	assert.Equal(models.Course{Code: "CPSC 221", Department: "CPSC", Number: "221"}, info, "there's no metadata yet")
+
+	var metadata database.CourseMetadata
+	assert.NoError(database.LoadJSONFile("test-course-metadata.json", &metadata))
+	withMetadata := c.WithCourseMetadata(metadata)
+	info, _ = c.CourseInfo("CPSC 221")
+	assert.Zero(info.Title, "the catalog itself doesn't change")
+	c = withMetadata
+	info, ok = c.CourseInfo("CPSC 221")
+	assert.True(ok)
+	assert.Equal("CPSC 221", info.Code)
+	assert.Equal("CPSC", info.Department)
+	assert.Equal("221", info.Number)
+	assert.Equal("Basic Algorithms and Data Structures", info.Title)
+	assert.Equal(4.0, info.Credits)
+
+	info, ok = c.CourseInfo("CPSC 448A")
+	assert.True(ok)
+	assert.Equal("448A", info.Number)
+	assert.Zero(info.Credits)
+
+	var credits database.CourseCredits
+	assert.NoError(database.LoadJSONFile("test-course-credits.json", &credits))
+	c = c.WithCourseCredits(database.CourseCredits{"CPSC 221": 5, "CPSC 448A": 1.5})
+	info, _ = c.CourseInfo("CPSC 221")
+	assert.Equal(5.0, info.Credits, "credits override the metadata")
+	info, _ = c.CourseInfo("CPSC 448A")
+	assert.Equal(1.5, info.Credits)
+	c = c.WithCourseCredits(credits)
+	info, _ = c.CourseInfo("APSC 100")
+	assert.Equal(3.0, info.Credits)
+
+	_, ok = c.CourseInfo("not a course")
+	assert.False(ok, "metadata of courses which don't exist is ignored")
+}
+
+func TestCourseCatalog_KnownActivities(t *testing.T) {
+	assert := assert.New(t)
+	database.LoadLocalDatabase("test-coursedb.json")
+
+	c := currentSnapshot(t).Catalog
+	for _, name := range c.CourseNames() {
+		for _, s := range c.Course(name).Sections {
+			for _, m := range s.Meetings {
+				assert.NotEqualf(models.UnknownActivity, models.ParseActivityType(m.Activity), "%s has an unknown activity %q", s.Name, m.Activity)
+			}
+		}
+	}
+}
+
+func TestCatalogLinked(t *testing.T) {
+	assert := assert.New(t)
+	section := func(activity, term string) database.Section {
+		return database.Section{
+			Activity:  []string{activity},
+			Days:      []string{"Mon"},
+			StartTime: []string{"9:00"},
+			EndTime:   []string{"10:00"},
+			Term:      []string{term},
+		}
+	}
+	c := database.NewCatalog(database.CourseDatabase{
+		"CPSC": {
+			"CPSC 121": {
+				"CPSC 121 101": section("Lecture", "1"),
+				"CPSC 121 102": section("Lecture", "1"),
+				"CPSC 121 201": section("Lecture", "2"),
+				"CPSC 121 001": section("Lecture", "1-2"),
+				"CPSC 121 L1A": section("Laboratory", "1"),
+				"CPSC 121 L2A": section("Laboratory", "2"),
+				"CPSC 121 L2B": section("Laboratory", ""),
+				"CPSC 121 T22": section("Tutorial", "1"),
+				"CPSC 121 T01": section("Tutorial", "1"),
+			},
+		},
+	})
+
+	assert.True(c.Linked("CPSC 121 101", "CPSC 121 L1A"))
+	assert.True(c.Linked("CPSC 121 102", "CPSC 121 L1A"))
+	assert.True(c.Linked("CPSC 121 201", "CPSC 121 L2A"))
+	assert.False(c.Linked("CPSC 121 101", "CPSC 121 L2A"), "sections in different terms aren't linked")
+	assert.False(c.Linked("CPSC 121 101", "CPSC 121 L2B"), "L2B has no term and is coded for term 2 lectures")
+	assert.True(c.Linked("CPSC 121 201", "CPSC 121 L2B"))
+	assert.True(c.Linked("CPSC 121 101", "CPSC 121 T22"), "the term of a section decides over its code")
+	assert.False(c.Linked("CPSC 121 201", "CPSC 121 T22"))
+	assert.True(c.Linked("CPSC 121 001", "CPSC 121 L1A"), "a 1-2 lecture shares a term with term 1 labs")
+	assert.True(c.Linked("CPSC 121 001", "CPSC 121 L2A"))
+	assert.True(c.Linked("CPSC 121 101", "CPSC 121 T01"), "codes without a term aren't restricted")
+	assert.False(c.Linked("CPSC 121 201", "CPSC 121 T01"))
+	assert.False(c.Linked("CPSC 121 101", "bogus"))
+
+	linked := c.WithSectionLinks(database.SectionLinks{"CPSC 121 L1A": {"CPSC 121 102"}})
+	assert.False(linked.Linked("CPSC 121 101", "CPSC 121 L1A"), "explicit links should override the convention")
+	assert.True(linked.Linked("CPSC 121 102", "CPSC 121 L1A"))
+	assert.True(linked.Linked("CPSC 121 101", "CPSC 121 T01"))
+	assert.True(c.Linked("CPSC 121 101", "CPSC 121 L1A"), "the catalog itself doesn't change")
+}
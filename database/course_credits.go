@@ -0,0 +1,8 @@
+package database
+
+// courseCreditsFile is the name of the optional course credits file next to the course database.
+const courseCreditsFile = "course-credits.json"
+
+// CourseCredits maps a course to its credits, overriding the credits in the course metadata.
+// e.g. {"CPSC 221": 4, "MATH 220": 3}
+type CourseCredits map[string]float64
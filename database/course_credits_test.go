@@ -0,0 +1,21 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/smart-cs/scheduler-backend/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadCourseCredits(t *testing.T) {
+	assert := assert.New(t)
+
+	var credits database.CourseCredits
+	assert.NoError(database.LoadJSONFile("test-course-credits.json", &credits))
+	assert.Len(credits, 8)
+	assert.Equal(4.0, credits["CPSC 221"])
+	assert.Equal(3.0, credits["MATH 220"])
+
+	assert.Error(database.LoadJSONFile("bad/path/to/credits", &credits))
+	assert.Error(database.LoadJSONFile("test-course-metadata.json", &credits), "course metadata isn't a credits file")
+}
@@ -0,0 +1,10 @@
+package database
+
+import "github.com/smart-cs/scheduler-backend/models"
+
+// courseMetadataFile is the name of the optional course metadata file next to the course database.
+const courseMetadataFile = "course-metadata.json"
+
+// CourseMetadata maps a course to its title, credits, description and prerequisites.
+// e.g. {"CPSC 221": {"title": "Basic Algorithms and Data Structures", "credits": 4}}
+type CourseMetadata map[string]models.Course
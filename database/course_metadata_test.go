@@ -0,0 +1,23 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/smart-cs/scheduler-backend/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadCourseMetadata(t *testing.T) {
+	assert := assert.New(t)
+
+	var metadata database.CourseMetadata
+	assert.NoError(database.LoadJSONFile("test-course-metadata.json", &metadata))
+	assert.Len(metadata, 6)
+	assert.Equal("Basic Algorithms and Data Structures", metadata["CPSC 221"].Title)
+	assert.Equal(4.0, metadata["CPSC 221"].Credits)
+	assert.Equal(3.0, metadata["MATH 220"].Credits)
+	assert.Empty(metadata["BIOL 111"].Description)
+
+	assert.Error(database.LoadJSONFile("bad/path/to/metadata", &metadata))
+	assert.Error(database.LoadJSONFile("test-section-links.json", &metadata), "section links aren't course metadata")
+}
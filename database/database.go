@@ -1,15 +1,14 @@
 package database
 
 import (
-	"bufio"
 	"encoding/json"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 )
 
 const defaultDatabasePath = "database/coursedb.json"
 
-var courseDB *CourseDatabase
-
 // Section is a Section of a UBC course.
 type Section struct {
 	Activity  []string `json:"activity"`
@@ -24,49 +23,101 @@ type Section struct {
 // CourseDatabase is the schema for our courses database.
 // Schema: DEPARTMENT_NAME -> COURSE_NAME -> COURSE_SECTION_NAME -> Section
 // e.g. DB["CPSC"]["CPSC 121"]["CPSC 121 101"] to get the underlying Section.
-type CourseDatabase map[string]map[string]map[string]interface{}
+type CourseDatabase map[string]map[string]map[string]Section
 
-// ParseSection returns a section from an interface.
-func ParseSection(section interface{}) Section {
-	b, err := json.Marshal(section)
+// ValidCourses returns the valid courses.
+func ValidCourses() ([]string, error) {
+	catalog, err := CourseCatalog()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	var parsedSection Section
-	err = json.Unmarshal(b, &parsedSection)
+	return catalog.CourseNames(), nil
+}
+
+// CourseDB returns the CourseDatabase of the current Snapshot, see Open.
+func CourseDB() (CourseDatabase, error) {
+	snapshot, err := Open()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return parsedSection
+	return snapshot.DB, nil
 }
 
-// ValidCourses returns the valid courses.
-func ValidCourses() []string {
-	var validCourses []string
-	for _, sectionMap := range CourseDB() {
-		for sectionName := range sectionMap {
-			validCourses = append(validCourses, sectionName)
-		}
+// CourseCatalog returns the Catalog of the current Snapshot, see Open.
+func CourseCatalog() (*Catalog, error) {
+	snapshot, err := Open()
+	if err != nil {
+		return nil, err
 	}
-	return validCourses
+	return snapshot.Catalog, nil
 }
 
-// CourseDB returns the CourseDatabase.
-func CourseDB() CourseDatabase {
-	if courseDB == nil {
-		LoadLocalDatabase(defaultDatabasePath)
+// LoadLocalDatabase loads the database from the given file path, along with the section links in section-links.json,
+// the course metadata in course-metadata.json and the credits in course-credits.json next to it if they exist.
+// The database becomes the current Snapshot. Errors are a *LoadError, the current Snapshot is kept if there's one.
+func LoadLocalDatabase(dbPath string) error {
+	_, err := load(dbPath)
+	return err
+}
+
+// LoadCourseDatabase loads a CourseDatabase from the given file path without making it the current Snapshot.
+// Errors are a *LoadError.
+func LoadCourseDatabase(dbPath string) (CourseDatabase, error) {
+	data, err := ioutil.ReadFile(dbPath)
+	if err != nil {
+		return nil, newLoadError(dbPath, err)
 	}
-	return *courseDB
+	return decodeDatabase(dbPath, data)
 }
 
-// LoadLocalDatabase loads the database from the given file path
-func LoadLocalDatabase(dbPath string) {
-	f, err := os.Open(dbPath)
+// LoadJSONFile decodes the JSON file at the given path into v, e.g. a *SectionLinks, *CourseMetadata or *CourseCredits.
+func LoadJSONFile(path string, v interface{}) error {
+	b, err := ioutil.ReadFile(path)
 	if err != nil {
-		panic("can't initialize database")
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// loadJSONNextTo decodes the file with the name next to the database into v, leaving v unchanged if there's no such file.
+// Errors are a *LoadError.
+func loadJSONNextTo(dbPath, name string, v interface{}) error {
+	path := filepath.Join(filepath.Dir(dbPath), name)
+	if err := LoadJSONFile(path, v); err != nil && !os.IsNotExist(err) {
+		return newLoadError(path, err)
+	}
+	return nil
+}
+
+// readDatabase reads the database and the files next to it into a Snapshot without a version.
+func readDatabase(dbPath string) (*Snapshot, error) {
+	modTime, err := databaseModTime(dbPath)
+	if err != nil {
+		return nil, newLoadError(dbPath, err)
+	}
+	db, err := LoadCourseDatabase(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	var links SectionLinks
+	if err := loadJSONNextTo(dbPath, sectionLinksFile, &links); err != nil {
+		return nil, err
+	}
+	var metadata CourseMetadata
+	if err := loadJSONNextTo(dbPath, courseMetadataFile, &metadata); err != nil {
+		return nil, err
+	}
+	var credits CourseCredits
+	if err := loadJSONNextTo(dbPath, courseCreditsFile, &credits); err != nil {
+		return nil, err
 	}
+	catalog := NewCatalog(db).WithSectionLinks(links).WithCourseMetadata(metadata).WithCourseCredits(credits)
 
-	var db CourseDatabase
-	json.NewDecoder(bufio.NewReader(f)).Decode(&db)
-	courseDB = &db
+	return &Snapshot{
+		Path:     dbPath,
+		ModTime:  modTime,
+		DB:       db,
+		Catalog:  catalog,
+		Problems: FindProblems(db),
+	}, nil
 }
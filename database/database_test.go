@@ -9,14 +9,17 @@ import (
 
 func TestDatabase(t *testing.T) {
 	assert := assert.New(t)
-	assert.NotPanics(func() { database.LoadLocalDatabase("test-coursedb.json") })
-	assert.NotZero(len(database.CourseDB()))
+	assert.NoError(database.LoadLocalDatabase("test-coursedb.json"))
+	db, err := database.CourseDB()
+	assert.NoError(err)
+	assert.NotZero(len(db))
 }
 
 func TestValidCourses(t *testing.T) {
 	assert := assert.New(t)
 	database.LoadLocalDatabase("test-coursedb.json")
-	validCourses := database.ValidCourses()
+	validCourses, err := database.ValidCourses()
+	assert.NoError(err)
 	assert.Contains(validCourses, "CPSC 121")
 	assert.Contains(validCourses, "MATH 100")
 	assert.Contains(validCourses, "MATH 101")
@@ -25,5 +28,15 @@ func TestValidCourses(t *testing.T) {
 
 func TestLoadLocalDatabase(t *testing.T) {
 	assert := assert.New(t)
-	assert.Panics(func() { database.LoadLocalDatabase("bad/path/to/database") })
+	assert.NoError(database.LoadLocalDatabase("test-coursedb.json"))
+	snapshot := currentSnapshot(t)
+	for _, p := range snapshot.Problems {
+		assert.Equal(database.SeverityWarning, p.Severity, "%v", p)
+	}
+
+	err := database.LoadLocalDatabase("bad/path/to/database")
+	assert.Error(err)
+	assert.IsType(&database.LoadError{}, err)
+	assert.Equal("bad/path/to/database", err.(*database.LoadError).Path)
+	assert.True(snapshot == currentSnapshot(t), "the current snapshot is kept")
 }
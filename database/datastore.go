@@ -1,13 +1,6 @@
 package database
 
-import (
-	"fmt"
-	"strconv"
-	"strings"
-
-	"github.com/pkg/errors"
-	"github.com/smart-cs/scheduler-backend/models"
-)
+import "github.com/smart-cs/scheduler-backend/models"
 
 // Datastore provides read operations from the datastore.
 type Datastore interface {
@@ -15,117 +8,175 @@ type Datastore interface {
 	// Possible terms: 1, 2, 1-2.
 	GetSections(courseName, term string, activityTypes ...models.ActivityType) []models.CourseSection
 
+	// FindSections returns the sections matching the query.
+	FindSections(query SectionQuery) []models.CourseSection
+
+	// GetCourse returns the information about a course, false if it doesn't exist.
+	GetCourse(courseName string) (models.Course, bool)
+
 	// CourseExists returns if the course name exists in the datastore, case sensenitive.
 	CourseExists(courseName string) bool
 
+	// SectionExists returns if the section name exists in the datastore, case sensitive.
+	SectionExists(sectionName string) bool
+
 	// CourseHasSectionWithActivty returns true if the course has a section with the given activity type.
 	CourseHasSectionWithActivity(courseName string, activity models.ActivityType) bool
+
+	// SectionsLinked returns true if a companion section (e.g. a Laboratory) can be registered in
+	// along with a primary section (e.g. a Lecture).
+	SectionsLinked(primary, companion string) bool
+}
+
+// SectionQuery selects sections of a course.
+type SectionQuery struct {
+	Course string
+	// Term must be 1, 2, 1-2
+	Term string
+	// Activities of the sections, sections with any other activity are excluded.
+	Activities []models.ActivityType
+	// ExcludeStatuses are the statuses of sections to exclude, unless they're pinned.
+	ExcludeStatuses []models.SectionStatus
+	// Pinned are names of sections which must be chosen. If one of them is a section of the course
+	// with one of the activities, the other sections with those activities are excluded.
+	Pinned []string
+	// Excluded are names of sections to exclude.
+	Excluded []string
 }
 
 // DefaultDatastore is the default implementation of Datastore.
 type DefaultDatastore struct {
-	db     CourseDatabase
-	helper models.CourseHelper
+	catalog *Catalog
+	helper  models.CourseHelper
+}
+
+// NewDatastore returns a Datastore leveraging an in-memory database, the current Snapshot's.
+func NewDatastore() (Datastore, error) {
+	catalog, err := CourseCatalog()
+	if err != nil {
+		return nil, err
+	}
+	return NewCatalogDatastore(catalog), nil
 }
 
-// NewDatastore returns a Datastore leveraging an in-memory database.
-func NewDatastore() Datastore {
+// NewCatalogDatastore returns a Datastore reading from the given Catalog.
+func NewCatalogDatastore(catalog *Catalog) Datastore {
 	return &DefaultDatastore{
-		db:     CourseDB(),
-		helper: models.CourseHelper{},
+		catalog: catalog,
+		helper:  models.CourseHelper{},
 	}
 }
 
 // GetSections returns sections of a course with one of the specified types, thats in terms.
 func (ds *DefaultDatastore) GetSections(courseName, term string, activityTypes ...models.ActivityType) []models.CourseSection {
-	if !ds.CourseExists(courseName) || (term != "1" && term != "2" && term != "1-2") {
+	return ds.FindSections(SectionQuery{
+		Course:     courseName,
+		Term:       term,
+		Activities: activityTypes,
+	})
+}
+
+// FindSections returns the sections matching the query.
+func (ds *DefaultDatastore) FindSections(query SectionQuery) []models.CourseSection {
+	course := ds.catalog.Course(query.Course)
+	term := query.Term
+	if course == nil || (term != "1" && term != "2" && term != "1-2") {
 		return []models.CourseSection{}
 	}
 
-	var sections []models.CourseSection
-	dept := strings.Split(courseName, " ")[0]
-	for sectionName, section := range ds.db[dept][courseName] {
-		if !strings.HasPrefix(sectionName, courseName) {
-			continue
-		}
-		s := ParseSection(section)
-		if !ds.helper.IsIncluded(s.Activity[0], activityTypes) {
-			continue
+	pinned := make(map[string]bool)
+	for _, name := range query.Pinned {
+		s := ds.catalog.Section(name)
+		if s != nil && s.Course == course.Name && ds.helper.IsIncluded(s.Activity, query.Activities) {
+			pinned[name] = true
 		}
+	}
 
-		if (term == "1" || term == "2") && s.Term[0] != term {
+	var sections []models.CourseSection
+	for i, activity := range query.Activities {
+		if ds.helper.IsIncluded(activity.String(), query.Activities[:i]) {
+			// Already added the sections of this activity.
 			continue
 		}
+		for _, s := range course.SectionsWithActivity(activity.String()) {
+			if s.Terms()&models.ParseTermSet(term) == 0 {
+				continue
+			}
+			if len(pinned) != 0 && !pinned[s.Name] {
+				continue
+			}
+			if !pinned[s.Name] && hasStatus(s.Status, query.ExcludeStatuses) {
+				continue
+			}
+			if models.ContainsName(query.Excluded, s.Name) {
+				continue
+			}
 
-		sessions, err := ds.sessions(s)
-		if err != nil {
-			fmt.Printf("WARNING: failed validating fields for %q: %s\n", sectionName, err.Error())
-		}
-
-		section := models.CourseSection{
-			Name:     sectionName,
-			Sessions: sessions,
+			section := models.CourseSection{
+				Name:     s.Name,
+				Sessions: ds.sessions(s),
+				Status:   s.Status,
+			}
+			sections = append(sections, section)
 		}
-		sections = append(sections, section)
 	}
 	return sections
 }
 
+// GetCourse returns the information about a course, false if it doesn't exist.
+func (ds *DefaultDatastore) GetCourse(courseName string) (models.Course, bool) {
+	return ds.catalog.CourseInfo(courseName)
+}
+
 // CourseExists returns if the course name is valid.
 func (ds *DefaultDatastore) CourseExists(courseName string) bool {
-	dept := strings.Split(courseName, " ")[0]
-	_, present := ds.db[dept][courseName]
-	return present
+	return ds.catalog.Course(courseName) != nil
+}
+
+// SectionExists returns if the section name is valid.
+func (ds *DefaultDatastore) SectionExists(sectionName string) bool {
+	return ds.catalog.Section(sectionName) != nil
 }
 
 // CourseHasSectionWithActivity returns the courses if it has the ActivityType.
 func (ds *DefaultDatastore) CourseHasSectionWithActivity(courseName string, activity models.ActivityType) bool {
-	dept := strings.Split(courseName, " ")[0]
-	for sectionName, section := range ds.db[dept][courseName] {
-		if !strings.HasPrefix(sectionName, courseName) {
-			continue
-		}
-		s := ParseSection(section)
-		if len(s.Activity) == 0 {
-			fmt.Printf("WARNING: CourseHasSectionWithActivity(%s, %s) section has no activity\n", courseName, activity)
-			continue
-		}
-
-		if s.Activity[0] == activity.String() {
-			return true
-		}
-	}
-	return false
+	course := ds.catalog.Course(courseName)
+	return course != nil && len(course.SectionsWithActivity(activity.String())) != 0
 }
 
-func (ds *DefaultDatastore) sessions(s Section) ([]models.ClassSession, error) {
+// sessions returns a ClassSession for every day of every meeting of the section.
+// Meetings without days or times (e.g. Thesis, Distance Education) have no fixed
+// time slot so they can't conflict with anything and don't produce sessions.
+func (ds *DefaultDatastore) sessions(s *CatalogSection) []models.ClassSession {
 	var sessions []models.ClassSession
-	for i, dayStr := range s.Days {
-		// dayStr looks like "Mon Wed Fri".
-		for _, day := range strings.Split(dayStr, " ") {
-			start, err := parseTime(s.StartTime[0])
-			if err != nil {
-				return nil, errors.Wrap(err, "no startTime")
-			}
-			end, err := parseTime(s.EndTime[0])
-			if err != nil {
-				return nil, errors.Wrap(err, "no endTime")
-			}
+	for _, m := range s.Meetings {
+		if !m.Timed {
+			continue
+		}
+		for _, day := range m.Days {
 			session := models.ClassSession{
-				Activity: s.Activity[i],
-				Term:     s.Term[i],
+				Activity: m.Activity,
+				Term:     m.Term,
 				Day:      day,
-				Start:    start,
-				End:      end,
+				Start:    m.Start,
+				End:      m.End,
 			}
 			sessions = append(sessions, session)
 		}
 	}
-	return sessions, nil
+	return sessions
+}
+
+func hasStatus(status models.SectionStatus, statuses []models.SectionStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
 }
 
-// praseTime parses time in the format HH:MM to an int HHMM.
-func parseTime(time string) (int, error) {
-	parsed, err := strconv.Atoi(strings.Replace(time, ":", "", -1))
-	return parsed, err
+// SectionsLinked returns true if the companion section can be registered in along with the primary section.
+func (ds *DefaultDatastore) SectionsLinked(primary, companion string) bool {
+	return ds.catalog.Linked(primary, companion)
 }
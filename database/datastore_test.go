@@ -12,10 +12,23 @@ func setup() {
 	database.LoadLocalDatabase("test-coursedb.json")
 }
 
+// currentSnapshot returns the current Snapshot, failing the test if there's none.
+func currentSnapshot(t *testing.T) *database.Snapshot {
+	snapshot, err := database.Open()
+	assert.NoError(t, err)
+	return snapshot
+}
+
+func newDatastore(t *testing.T) database.Datastore {
+	ds, err := database.NewDatastore()
+	assert.NoError(t, err)
+	return ds
+}
+
 func TestGetSections(t *testing.T) {
 	setup()
 	assert := assert.New(t)
-	ds := database.NewDatastore()
+	ds := newDatastore(t)
 
 	assert.Len(ds.GetSections("CPSC 110", "1-2", models.Lecture), 8)
 	assert.Len(ds.GetSections("CPSC 110", "1-2", models.Laboratory), 53)
@@ -52,7 +65,7 @@ func TestGetSections(t *testing.T) {
 func TestCourseExists(t *testing.T) {
 	setup()
 	assert := assert.New(t)
-	ds := database.NewDatastore()
+	ds := newDatastore(t)
 
 	assert.True(ds.CourseExists("CPSC 110"))
 	assert.True(ds.CourseExists("MATH 100"))
@@ -66,7 +79,7 @@ func TestCourseExists(t *testing.T) {
 func TestCourseHasSectionWithActivity(t *testing.T) {
 	setup()
 	assert := assert.New(t)
-	ds := database.NewDatastore()
+	ds := newDatastore(t)
 
 	assert.True(ds.CourseHasSectionWithActivity("CPSC 110", models.Lecture))
 	assert.True(ds.CourseHasSectionWithActivity("CPSC 110", models.Laboratory))
@@ -88,3 +101,138 @@ func TestCourseHasSectionWithActivity(t *testing.T) {
 	assert.False(ds.CourseHasSectionWithActivity("bogus", models.Laboratory))
 	assert.False(ds.CourseHasSectionWithActivity("bogus", models.Tutorial))
 }
+
+func TestGetSections_MultipleMeetings(t *testing.T) {
+	setup()
+	assert := assert.New(t)
+	ds := newDatastore(t)
+
+	t.Log("every meeting should use its own start and end time")
+	sections := ds.GetSections("APSC 100", "1", models.Lecture)
+	section := findSection(sections, "APSC 100 104")
+	assert.Equal([]models.ClassSession{
+		{Activity: "Lecture", Term: "1", Day: "Wed", Start: 800, End: 900},
+		{Activity: "Lecture", Term: "1", Day: "Fri", Start: 1100, End: 1200},
+	}, section.Sessions)
+
+	t.Log("meetings with blank times should be skipped without dropping the timed meetings")
+	sections = ds.GetSections("DHYG 412", "1-2", models.Lecture)
+	section = findSection(sections, "DHYG 412 001")
+	assert.Equal([]models.ClassSession{
+		{Activity: "Lecture", Term: "1", Day: "Tue", Start: 1300, End: 1600},
+	}, section.Sessions)
+
+	sections = ds.GetSections("DHYG 210", "1", models.Lecture)
+	section = findSection(sections, "DHYG 210 001")
+	assert.Len(section.Sessions, 7)
+	assert.Contains(section.Sessions, models.ClassSession{Activity: "Lecture", Term: "2", Day: "Wed", Start: 1400, End: 1700})
+
+	t.Log("sections without days or times should have no sessions")
+	sections = ds.GetSections("AANB 515", "2", models.Lecture)
+	section = findSection(sections, "AANB 515 002")
+	assert.Equal("AANB 515 002", section.Name)
+	assert.Empty(section.Sessions)
+}
+
+func findSection(sections []models.CourseSection, name string) models.CourseSection {
+	for _, s := range sections {
+		if s.Name == name {
+			return s
+		}
+	}
+	return models.CourseSection{}
+}
+
+func TestFindSections(t *testing.T) {
+	setup()
+	assert := assert.New(t)
+	ds := newDatastore(t)
+
+	query := database.SectionQuery{
+		Course:     "CPSC 121",
+		Term:       "1",
+		Activities: []models.ActivityType{models.Lecture},
+	}
+	sections := ds.FindSections(query)
+	assert.Len(sections, 3)
+	assert.Equal(models.Full, findSection(sections, "CPSC 121 101").Status)
+	assert.Equal(models.Restricted, findSection(sections, "CPSC 121 102").Status)
+	assert.Equal(ds.GetSections("CPSC 121", "1", models.Lecture), sections)
+
+	query.ExcludeStatuses = []models.SectionStatus{models.Full}
+	sections = ds.FindSections(query)
+	assert.Len(sections, 1)
+	assert.Equal("CPSC 121 102", sections[0].Name)
+
+	query.ExcludeStatuses = []models.SectionStatus{models.Full, models.Restricted}
+	assert.Empty(ds.FindSections(query))
+}
+
+func TestFindSections_PinnedAndExcluded(t *testing.T) {
+	setup()
+	assert := assert.New(t)
+	ds := newDatastore(t)
+	names := func(sections []models.CourseSection) []string {
+		var n []string
+		for _, s := range sections {
+			n = append(n, s.Name)
+		}
+		return n
+	}
+
+	query := database.SectionQuery{
+		Course:          "CPSC 121",
+		Term:            "1",
+		Activities:      []models.ActivityType{models.Lecture},
+		ExcludeStatuses: []models.SectionStatus{models.Full},
+		Pinned:          []string{"CPSC 121 101", "CPSC 121 L1A"},
+	}
+	assert.Equal([]string{"CPSC 121 101"}, names(ds.FindSections(query)), "pinned sections shouldn't be excluded by status")
+
+	query.Activities = []models.ActivityType{models.Laboratory}
+	assert.Equal([]string{"CPSC 121 L1A"}, names(ds.FindSections(query)))
+
+	query.Activities = []models.ActivityType{models.Tutorial}
+	assert.Len(ds.FindSections(query), 11, "pins of other activities shouldn't restrict tutorials")
+
+	t.Log("pinning a section of term 1 should leave nothing in term 2")
+	query.Term = "2"
+	query.Activities = []models.ActivityType{models.Lecture}
+	assert.Empty(ds.FindSections(query))
+
+	query = database.SectionQuery{
+		Course:     "CPSC 121",
+		Term:       "1",
+		Activities: []models.ActivityType{models.Lecture},
+		Excluded:   []string{"CPSC 121 101", "CPSC 121 103", "MATH 100 101"},
+	}
+	assert.Equal([]string{"CPSC 121 102"}, names(ds.FindSections(query)))
+}
+
+func TestSectionExists(t *testing.T) {
+	setup()
+	assert := assert.New(t)
+	ds := newDatastore(t)
+
+	assert.True(ds.SectionExists("CPSC 121 101"))
+	assert.True(ds.SectionExists("CPSC 121 L1A"))
+	assert.False(ds.SectionExists("CPSC 121"))
+	assert.False(ds.SectionExists("cpsc 121 101"))
+	assert.False(ds.SectionExists("bogus"))
+}
+
+func TestSectionsLinked(t *testing.T) {
+	setup()
+	assert := assert.New(t)
+	ds := newDatastore(t)
+
+	assert.True(ds.SectionsLinked("CPSC 121 101", "CPSC 121 L1A"))
+	assert.True(ds.SectionsLinked("CPSC 121 201", "CPSC 121 T2A"))
+	assert.False(ds.SectionsLinked("CPSC 121 101", "CPSC 121 L2A"))
+	assert.False(ds.SectionsLinked("CPSC 121 201", "CPSC 121 T1A"))
+	assert.False(ds.SectionsLinked("CPSC 121 101", "bogus"))
+
+	t.Log("BIOL 200 T22 is a term 1 tutorial of the term 1 lectures")
+	assert.True(ds.SectionsLinked("BIOL 200 101", "BIOL 200 T22"))
+	assert.True(ds.SectionsLinked("BIOL 200 105", "BIOL 200 T26"))
+}
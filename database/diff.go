@@ -0,0 +1,136 @@
+package database
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/smart-cs/scheduler-backend/models"
+)
+
+// Fields of SectionChange.
+const (
+	FieldStatus   = "status"
+	FieldActivity = "activity"
+	FieldDays     = "days"
+	FieldTimes    = "times"
+	FieldTerm     = "term"
+)
+
+// Diff is the changes from one CourseDatabase to another.
+type Diff struct {
+	AddedCourses   []string `json:"added_courses"`
+	RemovedCourses []string `json:"removed_courses"`
+	// AddedSections and RemovedSections are the sections added to or removed from the courses in both databases.
+	AddedSections   []string        `json:"added_sections"`
+	RemovedSections []string        `json:"removed_sections"`
+	Changes         []SectionChange `json:"changes"`
+}
+
+// SectionChange is a change of a field of a section in both databases.
+type SectionChange struct {
+	Section string `json:"section"`
+	// Field is the field which changed, one of the Field constants. e.g. 'status'
+	Field string `json:"field"`
+	// From and To are the values of the field with a value for every meeting. e.g. 'Full' -> 'Available'
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Empty returns true if nothing changed.
+func (d Diff) Empty() bool {
+	return len(d.AddedCourses) == 0 && len(d.RemovedCourses) == 0 &&
+		len(d.AddedSections) == 0 && len(d.RemovedSections) == 0 && len(d.Changes) == 0
+}
+
+// DiffDatabases returns the changes from one database to another, sorted by name.
+// The sections of added and removed courses aren't listed.
+func DiffDatabases(from, to CourseDatabase) Diff {
+	diff := Diff{
+		AddedCourses:    []string{},
+		RemovedCourses:  []string{},
+		AddedSections:   []string{},
+		RemovedSections: []string{},
+		Changes:         []SectionChange{},
+	}
+	oldCourses, newCourses := coursesOf(from), coursesOf(to)
+	for name := range newCourses {
+		if _, ok := oldCourses[name]; !ok {
+			diff.AddedCourses = append(diff.AddedCourses, name)
+		}
+	}
+	for name, oldSections := range oldCourses {
+		newSections, ok := newCourses[name]
+		if !ok {
+			diff.RemovedCourses = append(diff.RemovedCourses, name)
+			continue
+		}
+		for section := range newSections {
+			if _, ok := oldSections[section]; !ok {
+				diff.AddedSections = append(diff.AddedSections, section)
+			}
+		}
+		for section, o := range oldSections {
+			n, ok := newSections[section]
+			if !ok {
+				diff.RemovedSections = append(diff.RemovedSections, section)
+				continue
+			}
+			diff.Changes = append(diff.Changes, sectionChanges(section, o, n)...)
+		}
+	}
+
+	sort.Strings(diff.AddedCourses)
+	sort.Strings(diff.RemovedCourses)
+	sort.Strings(diff.AddedSections)
+	sort.Strings(diff.RemovedSections)
+	sort.SliceStable(diff.Changes, func(i, j int) bool {
+		return diff.Changes[i].Section < diff.Changes[j].Section
+	})
+	return diff
+}
+
+// coursesOf returns the sections of every course of the database.
+func coursesOf(db CourseDatabase) map[string]map[string]Section {
+	courses := make(map[string]map[string]Section)
+	for _, c := range db {
+		for name, sections := range c {
+			courses[name] = sections
+		}
+	}
+	return courses
+}
+
+// sectionChanges returns the changes of the fields of a section, in the order of the Field constants.
+func sectionChanges(name string, from, to Section) []SectionChange {
+	var changes []SectionChange
+	add := func(field, before, after string) {
+		if before != after {
+			changes = append(changes, SectionChange{Section: name, Field: field, From: before, To: after})
+		}
+	}
+	add(FieldStatus, statusName(from.Status), statusName(to.Status))
+	add(FieldActivity, strings.Join(from.Activity, ", "), strings.Join(to.Activity, ", "))
+	add(FieldDays, strings.Join(from.Days, ", "), strings.Join(to.Days, ", "))
+	add(FieldTimes, meetingTimes(from), meetingTimes(to))
+	add(FieldTerm, strings.Join(from.Term, ", "), strings.Join(to.Term, ", "))
+	return changes
+}
+
+// statusName returns the name of a status, statuses we don't know about are kept as they are.
+func statusName(status string) string {
+	if s := models.ParseSectionStatus(status); s != models.UnknownStatus {
+		return s.String()
+	}
+	return status
+}
+
+// meetingTimes returns the times of every meeting of a section. e.g. '9:00-10:00, 13:00-14:00'
+func meetingTimes(s Section) string {
+	times := make([]string, len(s.Activity))
+	for i := range s.Activity {
+		if start, end := field(s.StartTime, i), field(s.EndTime, i); start != "" || end != "" {
+			times[i] = start + "-" + end
+		}
+	}
+	return strings.Join(times, ", ")
+}
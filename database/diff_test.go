@@ -0,0 +1,57 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/smart-cs/scheduler-backend/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffDatabases(t *testing.T) {
+	assert := assert.New(t)
+	lecture := database.Section{
+		Activity:  []string{"Lecture"},
+		Days:      []string{"Mon Wed Fri"},
+		StartTime: []string{"9:00"},
+		EndTime:   []string{"10:00"},
+		Term:      []string{"1"},
+		Status:    "Full",
+	}
+	moved := lecture
+	moved.Days = []string{"Tue Thu"}
+	moved.StartTime = []string{"9:30"}
+	moved.EndTime = []string{"11:00"}
+	moved.Status = ""
+
+	old := database.CourseDatabase{
+		"CPSC": {
+			"CPSC 121": {"CPSC 121 101": lecture, "CPSC 121 102": lecture},
+			"CPSC 110": {"CPSC 110 101": lecture},
+		},
+	}
+	updated := database.CourseDatabase{
+		"CPSC": {
+			"CPSC 121": {"CPSC 121 101": moved, "CPSC 121 103": lecture},
+		},
+		"MATH": {
+			"MATH 100": {"MATH 100 101": lecture},
+		},
+	}
+
+	diff := database.DiffDatabases(old, updated)
+	assert.False(diff.Empty())
+	assert.Equal([]string{"MATH 100"}, diff.AddedCourses)
+	assert.Equal([]string{"CPSC 110"}, diff.RemovedCourses)
+	assert.Equal([]string{"CPSC 121 103"}, diff.AddedSections)
+	assert.Equal([]string{"CPSC 121 102"}, diff.RemovedSections)
+	assert.Equal([]database.SectionChange{
+		{Section: "CPSC 121 101", Field: database.FieldStatus, From: "Full", To: "Available"},
+		{Section: "CPSC 121 101", Field: database.FieldDays, From: "Mon Wed Fri", To: "Tue Thu"},
+		{Section: "CPSC 121 101", Field: database.FieldTimes, From: "9:00-10:00", To: "9:30-11:00"},
+	}, diff.Changes)
+
+	t.Log("a database has no changes from itself")
+	diff = database.DiffDatabases(old, old)
+	assert.True(diff.Empty())
+	assert.NotNil(diff.Changes)
+}
@@ -0,0 +1,130 @@
+package database
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// LoadError is an error loading a file of the database, with where in the file it happened.
+type LoadError struct {
+	// Path of the file. e.g. 'database/coursedb.json'
+	Path string
+	// Offset is the byte offset of the error in the file, 0 if it isn't known.
+	Offset int64
+	// Course and Section are the keys of the malformed entry, empty if it isn't known.
+	Course  string
+	Section string
+	Err     error
+}
+
+func (e *LoadError) Error() string {
+	parts := []string{e.Path}
+	if e.Offset > 0 {
+		parts = append(parts, fmt.Sprintf("offset %d", e.Offset))
+	}
+	if e.Course != "" {
+		parts = append(parts, "course "+e.Course)
+	}
+	if e.Section != "" {
+		parts = append(parts, "section "+e.Section)
+	}
+	return strings.Join(append(parts, e.Err.Error()), ": ")
+}
+
+// newLoadError returns a LoadError of a file with the offset of a JSON error.
+func newLoadError(path string, err error) *LoadError {
+	e := &LoadError{Path: path, Err: err}
+	switch err := err.(type) {
+	case *os.PathError:
+		// The path is already known.
+		e.Err = err.Err
+	case *json.SyntaxError:
+		e.Offset = err.Offset
+	case *json.UnmarshalTypeError:
+		e.Offset = err.Offset
+	}
+	return e
+}
+
+// decodeDatabase decodes a CourseDatabase, the error is a LoadError with the course and section
+// of the first section in the file which can't be decoded.
+func decodeDatabase(path string, data []byte) (CourseDatabase, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, &LoadError{Path: path, Err: fmt.Errorf("the database is empty")}
+	}
+	// Decoding the whole file first reports malformed JSON with its offset in the file.
+	var raw map[string]map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, newLoadError(path, err)
+	}
+
+	// The sections are decoded in the order of the file, so the error is about the first malformed
+	// section and its offset is where the decoder read it.
+	r := bytes.NewReader(data)
+	dec := json.NewDecoder(r)
+	offset := func() int64 {
+		buffered, _ := io.Copy(ioutil.Discard, dec.Buffered())
+		return int64(len(data)-r.Len()) - buffered
+	}
+	db := make(CourseDatabase, len(raw))
+	err := decodeObject(dec, func(deptName string) error {
+		if db[deptName] == nil {
+			db[deptName] = make(map[string]map[string]Section, len(raw[deptName]))
+		}
+		return decodeObject(dec, func(courseName string) error {
+			if db[deptName][courseName] == nil {
+				db[deptName][courseName] = make(map[string]Section, len(raw[deptName][courseName]))
+			}
+			return decodeObject(dec, func(sectionName string) error {
+				var s json.RawMessage
+				if err := dec.Decode(&s); err != nil {
+					return err
+				}
+				var section Section
+				if err := json.Unmarshal(s, &section); err != nil {
+					e := newLoadError(path, err)
+					e.Course, e.Section = courseName, sectionName
+					e.Offset += offset() - int64(len(s))
+					return e
+				}
+				db[deptName][courseName][sectionName] = section
+				return nil
+			})
+		})
+	})
+	if e, ok := err.(*LoadError); ok {
+		return nil, e
+	}
+	if err != nil {
+		return nil, &LoadError{Path: path, Offset: offset(), Err: err}
+	}
+	return db, nil
+}
+
+// decodeObject reads a JSON object or null from dec, calling entry with each key when the decoder
+// is at its value.
+func decodeObject(dec *json.Decoder, entry func(key string) error) error {
+	token, err := dec.Token()
+	if err != nil || token == nil {
+		return err
+	}
+	if token != json.Delim('{') {
+		return fmt.Errorf("expected an object, found %v", token)
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if err := entry(token.(string)); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
@@ -0,0 +1,73 @@
+package database_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/smart-cs/scheduler-backend/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func loadDatabase(t *testing.T, dir, content string) *database.LoadError {
+	path := filepath.Join(dir, "coursedb.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	err := database.LoadLocalDatabase(path)
+	if err == nil {
+		return nil
+	}
+	return err.(*database.LoadError)
+}
+
+func TestLoadError(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "load")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	defer database.LoadLocalDatabase("test-coursedb.json")
+	path := filepath.Join(dir, "coursedb.json")
+
+	t.Log("a missing file")
+	e := loadDatabase(t, dir, "")
+	assert.NotNil(e)
+	assert.Equal(path+": the database is empty", e.Error())
+	assert.NoError(os.Remove(path))
+	err = database.LoadLocalDatabase(path)
+	assert.Equal(path+": no such file or directory", err.Error())
+
+	t.Log("a truncated file has the offset where it ends")
+	e = loadDatabase(t, dir, `{"CPSC": {"CPSC 121": {`)
+	assert.NotNil(e)
+	assert.Equal(int64(23), e.Offset)
+	assert.Empty(e.Course)
+
+	t.Log("a malformed section has its course, section and offset")
+	e = loadDatabase(t, dir, `{"CPSC": {"CPSC 121": {"CPSC 121 101": {"activity": "Lecture"}}}}`)
+	assert.NotNil(e)
+	assert.Equal("CPSC 121", e.Course)
+	assert.Equal("CPSC 121 101", e.Section)
+	assert.Equal(int64(61), e.Offset, "the end of the malformed value")
+	assert.Contains(e.Error(), path+": offset 61: course CPSC 121: section CPSC 121 101: json: cannot unmarshal string")
+
+	t.Log("the first malformed section in the file is reported, with its own offset")
+	e = loadDatabase(t, dir, `{"MATH": {"MATH 100": {"MATH 100 101": {}, "MATH 100 102": {"activity": "Lab"}}},`+
+		` "CPSC": {"CPSC 121": {"CPSC 121 101": {"activity": "Lab"}}}}`)
+	assert.NotNil(e)
+	assert.Equal("MATH 100", e.Course)
+	assert.Equal("MATH 100 102", e.Section)
+	assert.Equal(int64(77), e.Offset, "not the offset of the later section with the same body")
+	for i := 0; i < 10; i++ {
+		again := loadDatabase(t, dir, `{"CPSC": {"CPSC 121": {"CPSC 121 101": {}, "CPSC 121 102": {"activity": "Lab"}},`+
+			` "CPSC 110": {"CPSC 110 101": {"activity": "Lab"}}}}`)
+		assert.Equal("CPSC 121 102", again.Section)
+		assert.Equal(int64(77), again.Offset)
+	}
+
+	t.Log("a malformed file next to the database")
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "course-credits.json"), []byte(`{"CPSC 121": "four"}`), 0644))
+	e = loadDatabase(t, dir, `{"CPSC": {"CPSC 121": {}}}`)
+	assert.NotNil(e)
+	assert.Equal(filepath.Join(dir, "course-credits.json"), e.Path)
+	assert.NotZero(e.Offset)
+}
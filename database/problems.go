@@ -0,0 +1,146 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/smart-cs/scheduler-backend/models"
+)
+
+// Rules of Problem.
+const (
+	// RuleColumnLength is broken by a section whose columns don't all have a row for every meeting.
+	RuleColumnLength = "column_length"
+	// RuleSectionKey is broken by a section whose name doesn't start with its course, it isn't loaded.
+	RuleSectionKey = "section_key"
+	// RuleTimeFormat is broken by a start or end time which isn't HH:MM, the meeting has no fixed time slot.
+	RuleTimeFormat = "time_format"
+	// RuleEndBeforeStart is broken by a meeting which doesn't end after it starts.
+	RuleEndBeforeStart = "end_before_start"
+	// RuleIntervalWhitespace is broken by an interval with line breaks, tabs or extra spaces.
+	RuleIntervalWhitespace = "interval_whitespace"
+	// RuleUnknownActivity is broken by a meeting with an activity which isn't a models.ActivityType.
+	RuleUnknownActivity = "unknown_activity"
+	// RuleUnknownStatus is broken by a section with a status which isn't a models.SectionStatus.
+	RuleUnknownStatus = "unknown_status"
+	// RuleUnknownTerm is broken by a meeting with a term which isn't 1, 2, 1-2 or a summer term.
+	RuleUnknownTerm = "unknown_term"
+)
+
+// Severities of Problem.
+const (
+	// SeverityError is the severity of a problem which changes how the section is scheduled.
+	SeverityError = "error"
+	// SeverityWarning is the severity of a problem which doesn't change how the section is scheduled.
+	SeverityWarning = "warning"
+)
+
+// Rules are all the rules of Problem in the order they are checked.
+var Rules = []string{
+	RuleSectionKey, RuleColumnLength, RuleUnknownActivity, RuleUnknownStatus, RuleUnknownTerm,
+	RuleTimeFormat, RuleEndBeforeStart, RuleIntervalWhitespace,
+}
+
+// RuleSeverity returns the severity of the problems of a rule.
+func RuleSeverity(rule string) string {
+	if rule == RuleIntervalWhitespace {
+		return SeverityWarning
+	}
+	return SeverityError
+}
+
+// Problem is a malformed section of the database. The section is still loaded as well as it can be,
+// unless the rule says otherwise.
+type Problem struct {
+	Course  string `json:"course"`
+	Section string `json:"section"`
+	// Rule is the rule the section breaks, one of the Rule constants. e.g. 'time_format'
+	Rule string `json:"rule"`
+	// Severity is the severity of the rule, one of the Severity constants. e.g. 'error'
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s: %s", p.Section, p.Rule, p.Message)
+}
+
+// FindProblems returns the problems of every section of the database, sorted by section and rule.
+func FindProblems(db CourseDatabase) []Problem {
+	var problems []Problem
+	for _, courses := range db {
+		for courseName, sections := range courses {
+			for sectionName, s := range sections {
+				problems = append(problems, sectionProblems(courseName, sectionName, s)...)
+			}
+		}
+	}
+	sort.Slice(problems, func(i, j int) bool {
+		if problems[i].Section != problems[j].Section {
+			return problems[i].Section < problems[j].Section
+		}
+		return problems[i].Rule < problems[j].Rule
+	})
+	return problems
+}
+
+// sectionProblems returns the problems of a section.
+func sectionProblems(course, name string, s Section) []Problem {
+	var problems []Problem
+	add := func(rule, format string, args ...interface{}) {
+		problems = append(problems, Problem{
+			Course:   course,
+			Section:  name,
+			Rule:     rule,
+			Severity: RuleSeverity(rule),
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	if !strings.HasPrefix(name, course) {
+		add(RuleSectionKey, "the section isn't named after course %s", course)
+	}
+	columns := map[string][]string{
+		"days":       s.Days,
+		"start_time": s.StartTime,
+		"end_time":   s.EndTime,
+		"term":       s.Term,
+	}
+	for _, column := range []string{"days", "start_time", "end_time", "term"} {
+		if len(columns[column]) != len(s.Activity) {
+			add(RuleColumnLength, "%s has %d rows but activity has %d", column, len(columns[column]), len(s.Activity))
+		}
+	}
+	if models.ParseSectionStatus(s.Status) == models.UnknownStatus {
+		add(RuleUnknownStatus, "unknown status %q", s.Status)
+	}
+	for i, activity := range s.Activity {
+		if models.ParseActivityType(activity) == models.UnknownActivity {
+			add(RuleUnknownActivity, "row %d has unknown activity %q", i, activity)
+		}
+		if term := field(s.Term, i); i < len(s.Term) && models.ParseTermSet(term) == 0 {
+			add(RuleUnknownTerm, "row %d has unknown term %q", i, term)
+		}
+
+		start, end := field(s.StartTime, i), field(s.EndTime, i)
+		if start == "" && end == "" {
+			// A meeting without a time, e.g. a thesis.
+			continue
+		}
+		startTime, startErr := models.ParseTime(start)
+		endTime, endErr := models.ParseTime(end)
+		for _, err := range []error{startErr, endErr} {
+			if err != nil {
+				add(RuleTimeFormat, "row %d has %v", i, err)
+			}
+		}
+		if startErr == nil && endErr == nil && endTime <= startTime {
+			add(RuleEndBeforeStart, "row %d ends at %s, not after it starts at %s", i, end, start)
+		}
+	}
+	if s.Interval != strings.Join(strings.Fields(s.Interval), " ") {
+		add(RuleIntervalWhitespace, "interval %q has line breaks, tabs or extra spaces", s.Interval)
+	}
+	return problems
+}
@@ -0,0 +1,87 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/smart-cs/scheduler-backend/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func problemRules(problems []database.Problem) []string {
+	var rules []string
+	for _, p := range problems {
+		rules = append(rules, p.Rule)
+	}
+	return rules
+}
+
+func TestFindProblems(t *testing.T) {
+	assert := assert.New(t)
+	db := database.CourseDatabase{"CPSC": {"CPSC 121": {
+		"CPSC 121 101": {
+			Activity:  []string{"Lecture", "Lecture"},
+			Days:      []string{"Mon", "Wed"},
+			StartTime: []string{"9:00", "9h00"},
+			EndTime:   []string{"10:00", "10:00"},
+			Term:      []string{"1"},
+		},
+		"CPSC 121 L1A": {
+			Activity:  []string{"Laboratory"},
+			Days:      []string{"Tue"},
+			StartTime: []string{""},
+			EndTime:   []string{""},
+			Term:      []string{"1"},
+			Status:    "Full",
+		},
+		"CPSC 121 T1A": {
+			Activity:  []string{"Tutorial", "Hackathon"},
+			Days:      []string{"Tue", "Thu"},
+			StartTime: []string{"13:00", "9:00"},
+			EndTime:   []string{"12:00", "10:00"},
+			Term:      []string{"1", "3"},
+			Status:    "Maybe",
+			Interval:  "P1 - MBA\n\t\tP2 - MBA",
+		},
+		"MATH 100 101": {},
+	}}}
+
+	problems := database.FindProblems(db)
+	assert.Equal(database.Problem{
+		Course:   "CPSC 121",
+		Section:  "CPSC 121 101",
+		Rule:     database.RuleColumnLength,
+		Severity: database.SeverityError,
+		Message:  "term has 1 rows but activity has 2",
+	}, problems[0])
+	assert.Equal(`CPSC 121 101: time_format: row 1 has invalid time "9h00", expected HH:MM`, problems[1].String())
+	assert.Equal([]string{
+		database.RuleColumnLength, database.RuleTimeFormat,
+		database.RuleEndBeforeStart, database.RuleIntervalWhitespace, database.RuleUnknownActivity,
+		database.RuleUnknownStatus, database.RuleUnknownTerm,
+		database.RuleSectionKey,
+	}, problemRules(problems))
+	assert.Equal("MATH 100 101", problems[len(problems)-1].Section)
+	assert.Equal(database.SeverityWarning, problems[3].Severity)
+	assert.Equal("row 0 ends at 12:00, not after it starts at 13:00", problems[2].Message)
+}
+
+func TestFindProblems_Times(t *testing.T) {
+	assert := assert.New(t)
+	problems := func(start, end string) []database.Problem {
+		return database.FindProblems(database.CourseDatabase{"CPSC": {"CPSC 121": {
+			"CPSC 121 101": {
+				Activity:  []string{"Lecture"},
+				Days:      []string{"Mon"},
+				StartTime: []string{start},
+				EndTime:   []string{end},
+				Term:      []string{"1"},
+			},
+		}}})
+	}
+
+	assert.Empty(problems("0900", "1000"), "times the catalog schedules aren't problems")
+	assert.Equal([]string{database.RuleTimeFormat, database.RuleTimeFormat}, problemRules(problems("25:00", "26:00")))
+	assert.Equal([]string{database.RuleTimeFormat}, problemRules(problems("9:00", "9:75")))
+	assert.Equal(`row 0 has invalid time "9:75", expected HH:MM`, problems("9:00", "9:75")[0].Message)
+	assert.Equal([]string{database.RuleEndBeforeStart}, problemRules(problems("10:00", "9:00")))
+}
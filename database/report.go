@@ -0,0 +1,51 @@
+package database
+
+// Report is the problems of a database grouped by rule.
+type Report struct {
+	// Path of the database file.
+	Path string `json:"path"`
+	// Sections is the number of sections in the database.
+	Sections int `json:"sections"`
+	// Errors and Warnings are the number of problems of each severity.
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+	// Rules are the rules with problems in the order of Rules.
+	Rules []RuleReport `json:"rules"`
+}
+
+// RuleReport is the problems of a rule.
+type RuleReport struct {
+	Rule     string    `json:"rule"`
+	Severity string    `json:"severity"`
+	Problems []Problem `json:"problems"`
+}
+
+// NewReport finds the problems of a database and groups them by rule.
+func NewReport(path string, db CourseDatabase) Report {
+	report := Report{Path: path, Rules: []RuleReport{}}
+	for _, courses := range db {
+		for _, sections := range courses {
+			report.Sections += len(sections)
+		}
+	}
+
+	byRule := make(map[string][]Problem)
+	for _, p := range FindProblems(db) {
+		byRule[p.Rule] = append(byRule[p.Rule], p)
+		if p.Severity == SeverityError {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+	for _, rule := range Rules {
+		if len(byRule[rule]) != 0 {
+			report.Rules = append(report.Rules, RuleReport{
+				Rule:     rule,
+				Severity: RuleSeverity(rule),
+				Problems: byRule[rule],
+			})
+		}
+	}
+	return report
+}
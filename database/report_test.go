@@ -0,0 +1,45 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/smart-cs/scheduler-backend/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReport(t *testing.T) {
+	assert := assert.New(t)
+	db := database.CourseDatabase{"CPSC": {"CPSC 121": {
+		"CPSC 121 101": {
+			Activity:  []string{"Lecture"},
+			Days:      []string{"Mon"},
+			StartTime: []string{"10:00"},
+			EndTime:   []string{"9:00"},
+			Term:      []string{"1"},
+			Interval:  " P1",
+		},
+		"CPSC 121 102": {
+			Activity:  []string{"Lecture"},
+			Days:      []string{"Mon"},
+			StartTime: []string{"11:00"},
+			EndTime:   []string{"11:00"},
+			Term:      []string{"1", "2"},
+		},
+	}}}
+
+	report := database.NewReport("coursedb.json", db)
+	assert.Equal("coursedb.json", report.Path)
+	assert.Equal(2, report.Sections)
+	assert.Equal(3, report.Errors)
+	assert.Equal(1, report.Warnings)
+	assert.Len(report.Rules, 3)
+	assert.Equal(database.RuleColumnLength, report.Rules[0].Rule)
+	assert.Equal(database.RuleEndBeforeStart, report.Rules[1].Rule)
+	assert.Len(report.Rules[1].Problems, 2)
+	assert.Equal(database.RuleIntervalWhitespace, report.Rules[2].Rule)
+	assert.Equal(database.SeverityWarning, report.Rules[2].Severity)
+
+	report = database.NewReport("empty.json", database.CourseDatabase{})
+	assert.NotNil(report.Rules)
+	assert.Zero(report.Errors)
+}
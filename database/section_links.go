@@ -0,0 +1,8 @@
+package database
+
+// sectionLinksFile is the name of the optional section links file next to the course database.
+const sectionLinksFile = "section-links.json"
+
+// SectionLinks maps a companion section to the only primary sections it can be taken with.
+// e.g. {"CPSC 121 L1A": ["CPSC 121 101"]}
+type SectionLinks map[string][]string
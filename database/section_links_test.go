@@ -0,0 +1,22 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/smart-cs/scheduler-backend/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadSectionLinks(t *testing.T) {
+	assert := assert.New(t)
+
+	var links database.SectionLinks
+	assert.NoError(database.LoadJSONFile("test-section-links.json", &links))
+	assert.Equal(database.SectionLinks{
+		"CPSC 121 L1A": {"CPSC 121 101"},
+		"CPSC 121 L1B": {"CPSC 121 101", "CPSC 121 102"},
+	}, links)
+
+	assert.Error(database.LoadJSONFile("bad/path/to/links", &links))
+	assert.Error(database.LoadJSONFile("test-coursedb.json", &links), "the course database isn't a links file")
+}
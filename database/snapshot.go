@@ -0,0 +1,128 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Snapshot is a loaded version of the database with the Catalog built from it.
+// Loading the database again swaps in a new Snapshot so readers holding one keep a consistent view,
+// a Snapshot and its Catalog never change.
+type Snapshot struct {
+	// Version is 1 for the first database loaded and increases with every load.
+	Version uint64
+	// Path of the database file.
+	Path string
+	// ModTime is the latest modification time of the database and the files next to it.
+	ModTime time.Time
+	DB      CourseDatabase
+	Catalog *Catalog
+	// Problems are the malformed sections of the database.
+	Problems []Problem
+}
+
+var (
+	// current holds the *Snapshot returned by Current.
+	current atomic.Value
+	// loading is held while loading a database so a database read earlier is never swapped in after a later one.
+	loading sync.Mutex
+	version uint64
+)
+
+// Open returns the current Snapshot, loading the default database if none was loaded yet.
+func Open() (*Snapshot, error) {
+	if s, ok := current.Load().(*Snapshot); ok {
+		return s, nil
+	}
+	return load(defaultDatabasePath)
+}
+
+// Reload loads the database of the current Snapshot again and swaps it in.
+// The current Snapshot is kept if the database can't be loaded.
+func Reload() (*Snapshot, error) {
+	snapshot, err := Open()
+	if err != nil {
+		return nil, err
+	}
+	return load(snapshot.Path)
+}
+
+// ReloadIfChanged reloads the database if it or one of the files next to it was modified since the current Snapshot.
+// Returns the current Snapshot and false if nothing changed.
+func ReloadIfChanged() (*Snapshot, bool, error) {
+	snapshot, err := Open()
+	if err != nil {
+		return nil, false, err
+	}
+	modTime, err := databaseModTime(snapshot.Path)
+	if err != nil {
+		return snapshot, false, err
+	}
+	if !modTime.After(snapshot.ModTime) {
+		return snapshot, false, nil
+	}
+	reloaded, err := Reload()
+	if err != nil {
+		return snapshot, false, err
+	}
+	return reloaded, true, nil
+}
+
+// Watch calls ReloadIfChanged every interval until stop is called, then calls reloaded with the result
+// if the database was reloaded or couldn't be.
+func Watch(interval time.Duration, reloaded func(*Snapshot, error)) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan bool)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				snapshot, changed, err := ReloadIfChanged()
+				if changed || err != nil {
+					reloaded(snapshot, err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+	}
+}
+
+// load reads the database and swaps it in as the current Snapshot.
+func load(dbPath string) (*Snapshot, error) {
+	loading.Lock()
+	defer loading.Unlock()
+	snapshot, err := readDatabase(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	version++
+	snapshot.Version = version
+	current.Store(snapshot)
+	return snapshot, nil
+}
+
+// databaseModTime returns the latest modification time of the database and the files next to it which exist.
+func databaseModTime(dbPath string) (time.Time, error) {
+	info, err := os.Stat(dbPath)
+	if err != nil {
+		return time.Time{}, err
+	}
+	latest := info.ModTime()
+	for _, name := range []string{sectionLinksFile, courseMetadataFile, courseCreditsFile} {
+		if info, err := os.Stat(filepath.Join(filepath.Dir(dbPath), name)); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
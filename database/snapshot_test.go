@@ -0,0 +1,92 @@
+package database_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/smart-cs/scheduler-backend/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func copyTestDatabase(t *testing.T, dir string) string {
+	data, err := ioutil.ReadFile("test-coursedb.json")
+	assert.NoError(t, err)
+	path := filepath.Join(dir, "coursedb.json")
+	assert.NoError(t, ioutil.WriteFile(path, data, 0644))
+	return path
+}
+
+func TestSnapshotReload(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "snapshot")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	defer database.LoadLocalDatabase("test-coursedb.json")
+
+	path := copyTestDatabase(t, dir)
+	database.LoadLocalDatabase(path)
+	first := currentSnapshot(t)
+	assert.Equal(path, first.Path)
+	assert.NotNil(first.Catalog.Course("CPSC 121"))
+
+	snapshot, changed, err := database.ReloadIfChanged()
+	assert.NoError(err)
+	assert.False(changed)
+	assert.True(first == snapshot)
+
+	t.Log("a modified database is loaded into a new snapshot")
+	assert.NoError(ioutil.WriteFile(path, []byte(`{"CPSC": {"CPSC 999": {}}}`), 0644))
+	later := first.ModTime.Add(time.Second)
+	assert.NoError(os.Chtimes(path, later, later))
+	snapshot, changed, err = database.ReloadIfChanged()
+	assert.NoError(err)
+	assert.True(changed)
+	assert.True(snapshot.Version > first.Version)
+	assert.True(currentSnapshot(t) == snapshot)
+	assert.NotNil(currentSnapshot(t).Catalog.Course("CPSC 999"))
+	assert.Nil(currentSnapshot(t).Catalog.Course("CPSC 121"))
+	assert.NotNil(first.Catalog.Course("CPSC 121"), "the old snapshot doesn't change")
+
+	t.Log("the current snapshot is kept if the database can't be loaded")
+	assert.NoError(ioutil.WriteFile(path, []byte(`{"CPSC": `), 0644))
+	_, err = database.Reload()
+	assert.Error(err)
+	assert.True(currentSnapshot(t) == snapshot)
+	latest := later.Add(time.Second)
+	assert.NoError(os.Chtimes(path, latest, latest))
+	current, changed, err := database.ReloadIfChanged()
+	assert.Error(err)
+	assert.False(changed)
+	assert.True(current == snapshot)
+}
+
+func TestSnapshotWatch(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "snapshot")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	defer database.LoadLocalDatabase("test-coursedb.json")
+
+	path := copyTestDatabase(t, dir)
+	database.LoadLocalDatabase(path)
+	first := currentSnapshot(t)
+
+	reloaded := make(chan *database.Snapshot, 1)
+	stop := database.Watch(10*time.Millisecond, func(s *database.Snapshot, err error) {
+		assert.NoError(err)
+		reloaded <- s
+	})
+	defer stop()
+
+	later := first.ModTime.Add(time.Second)
+	assert.NoError(os.Chtimes(path, later, later))
+	select {
+	case s := <-reloaded:
+		assert.True(s.Version > first.Version)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the database wasn't reloaded")
+	}
+}
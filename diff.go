@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/smart-cs/scheduler-backend/database"
+)
+
+// diff loads two course databases and prints the changes from the first to the second.
+// Returns the exit code like diff(1): 0 if nothing changed, 1 if something did and 2 if a database can't be loaded.
+func diff(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "print the changes as JSON")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: diff [-json] old-path new-path")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+
+	var dbs []database.CourseDatabase
+	for _, path := range flags.Args() {
+		db, err := database.LoadCourseDatabase(path)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+		dbs = append(dbs, db)
+	}
+	d := database.DiffDatabases(dbs[0], dbs[1])
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(d)
+	} else {
+		writeDiff(stdout, d)
+	}
+
+	if d.Empty() {
+		return 0
+	}
+	return 1
+}
+
+// writeDiff prints a line for every change, + for added, - for removed and ~ for changed, followed by a summary.
+func writeDiff(w io.Writer, d database.Diff) {
+	for _, c := range d.AddedCourses {
+		fmt.Fprintf(w, "+ %s\n", c)
+	}
+	for _, c := range d.RemovedCourses {
+		fmt.Fprintf(w, "- %s\n", c)
+	}
+	for _, s := range d.AddedSections {
+		fmt.Fprintf(w, "+ %s\n", s)
+	}
+	for _, s := range d.RemovedSections {
+		fmt.Fprintf(w, "- %s\n", s)
+	}
+	for _, c := range d.Changes {
+		fmt.Fprintf(w, "~ %s %s: %s -> %s\n", c.Section, c.Field, c.From, c.To)
+	}
+	fmt.Fprintf(w, "%d courses added, %d removed; %d sections added, %d removed, %d changes\n",
+		len(d.AddedCourses), len(d.RemovedCourses), len(d.AddedSections), len(d.RemovedSections), len(d.Changes))
+}
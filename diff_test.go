@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/smart-cs/scheduler-backend/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	assert := assert.New(t)
+	var stdout, stderr bytes.Buffer
+
+	t.Log("a database has no changes from itself")
+	assert.Equal(0, diff([]string{"database/test-coursedb.json", "database/test-coursedb.json"}, &stdout, &stderr))
+	assert.Equal("0 courses added, 0 removed; 0 sections added, 0 removed, 0 changes\n", stdout.String())
+
+	dir, err := ioutil.TempDir("", "diff")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	old, updated := filepath.Join(dir, "old.json"), filepath.Join(dir, "new.json")
+	assert.NoError(ioutil.WriteFile(old, []byte(`{"CPSC": {
+		"CPSC 121": {"CPSC 121 101": {"activity": ["Lecture"], "status": "Full"}},
+		"CPSC 110": {}
+	}}`), 0644))
+	assert.NoError(ioutil.WriteFile(updated, []byte(`{"CPSC": {
+		"CPSC 121": {"CPSC 121 101": {"activity": ["Lecture"], "status": ""}, "CPSC 121 102": {}}
+	}}`), 0644))
+
+	stdout.Reset()
+	assert.Equal(1, diff([]string{old, updated}, &stdout, &stderr))
+	assert.Equal(`- CPSC 110
++ CPSC 121 102
+~ CPSC 121 101 status: Full -> Available
+0 courses added, 1 removed; 1 sections added, 0 removed, 1 changes
+`, stdout.String())
+
+	t.Log("the changes as JSON")
+	stdout.Reset()
+	assert.Equal(1, diff([]string{"-json", old, updated}, &stdout, &stderr))
+	var d database.Diff
+	assert.NoError(json.Unmarshal(stdout.Bytes(), &d))
+	assert.Equal([]string{"CPSC 110"}, d.RemovedCourses)
+	assert.Equal("Available", d.Changes[0].To)
+
+	t.Log("two databases are required")
+	assert.Equal(2, diff([]string{old}, &stdout, &stderr))
+	assert.Equal(2, diff([]string{old, filepath.Join(dir, "missing.json")}, &stdout, &stderr))
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/smart-cs/scheduler-backend/models"
+	"github.com/smart-cs/scheduler-backend/schedules"
+	"github.com/smart-cs/scheduler-backend/server"
+)
+
+// Output formats of generate.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatGrid  = "grid"
+)
+
+// scheduleFlags are the query parameters of GET /schedules which generate takes as flags, besides the preferences.
+var scheduleFlags = []struct {
+	param string
+	usage string
+	// repeated parameters can be given more than once. e.g. -blocked 'Mon 9:00-10:00' -blocked 'Fri 13:00-17:00'
+	repeated bool
+	boolean  bool
+}{
+	{param: "term", usage: "term to create the schedules for: 1, 2 or 1-2 (default 1-2)"},
+	{param: "lectures_only", usage: "create schedules with only lectures (default true)", boolean: true},
+	{param: "optional", usage: "comma separated courses which can be left out if they don't fit", repeated: true},
+	{param: "pinned", usage: "comma separated sections which must be in every schedule", repeated: true},
+	{param: "excluded", usage: "comma separated sections which can't be in a schedule", repeated: true},
+	{param: "blocked", usage: "comma separated times where no class can be scheduled. e.g. 'Mon Wed 9:00-10:00'", repeated: true},
+	{param: "exclude_status", usage: "comma separated statuses of sections which can't be in a schedule (default Cancelled)"},
+	{param: "min_credits", usage: "fewest credits of each term, 0 for no limit"},
+	{param: "max_credits", usage: "most credits of each term, 0 for no limit"},
+	{param: "offset", usage: "schedules to skip before printing"},
+	{param: "limit", usage: "most schedules to print, 0 for all of them"},
+	{param: "explain", usage: "explain fully why there are no schedules", boolean: true},
+}
+
+// paramFlag is a flag setting a query parameter. e.g. -days-off Fri sets days_off=Fri
+type paramFlag struct {
+	params   url.Values
+	name     string
+	repeated bool
+	boolean  bool
+}
+
+func (f paramFlag) String() string {
+	return strings.Join(f.params[f.name], " ")
+}
+
+func (f paramFlag) Set(value string) error {
+	if f.repeated {
+		f.params.Add(f.name, value)
+	} else {
+		f.params.Set(f.name, value)
+	}
+	return nil
+}
+
+// IsBoolFlag lets boolean parameters be given without a value. e.g. -explain
+func (f paramFlag) IsBoolFlag() bool {
+	return f.boolean
+}
+
+// flagName returns the flag of a query parameter. e.g. 'days_off' -> 'days-off'
+func flagName(param string) string {
+	return strings.Replace(param, "_", "-", -1)
+}
+
+// generate prints the schedules of the courses in the arguments like GET /schedules, without starting the server.
+// The flags are the query parameters of GET /schedules, with dashes instead of underscores.
+// Returns 1 if there are no schedules, after explaining why, and 2 if the request is invalid.
+func generate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	catalog := flags.String("catalog", defaultDatabasePath, "path of the course database")
+	format := flags.String("format", formatTable, "output format: table, json or grid")
+	params := url.Values{"limit": {"10"}}
+	for _, f := range scheduleFlags {
+		flags.Var(paramFlag{params: params, name: f.param, repeated: f.repeated, boolean: f.boolean}, flagName(f.param), f.usage)
+	}
+	for _, name := range schedules.PreferenceNames {
+		flags.Var(paramFlag{params: params, name: name}, flagName(name), "argument of the "+name+" preference, like the query parameter")
+		flags.Var(paramFlag{params: params, name: name + "_weight"}, flagName(name+"_weight"), "weight of the "+name+" preference (default 1)")
+	}
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: generate [flags] course...   e.g. generate -term 1 -days-off Fri 'CPSC 221' 'MATH 220'")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *format != formatTable && *format != formatJSON && *format != formatGrid {
+		fmt.Fprintf(stderr, "format %q must be table, json or grid\n", *format)
+		return 2
+	}
+	for _, course := range flags.Args() {
+		params.Add("courses", course)
+	}
+	if !loadCatalog(*catalog, stderr) {
+		return 2
+	}
+
+	creator, err := schedules.NewScheduleCreator()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	query := server.ParseScheduleQuery(params)
+	resp := server.FindSchedules(creator, query)
+	if *format == formatJSON {
+		if err := writeJSON(stdout, resp); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	}
+	if !resp.OK {
+		if *format != formatJSON {
+			for _, e := range resp.Errors {
+				fmt.Fprintln(stderr, e.Message)
+			}
+		}
+		return 2
+	}
+
+	page := resp.Body.([]models.Schedule)
+	switch *format {
+	case formatGrid:
+		writeGrid(stdout, page, query.Offset)
+	case formatTable:
+		writeTable(stdout, page, query.Offset)
+	}
+	if resp.Diagnosis != nil {
+		if *format != formatJSON {
+			writeDiagnosis(stdout, *resp.Diagnosis)
+		}
+		return 1
+	}
+	if *resp.HasMore && *format != formatJSON {
+		fmt.Fprintf(stdout, "\nthere are more than %d schedules\n", query.Offset+len(page))
+	}
+	return 0
+}
+
+// writeJSON prints a value as indented JSON.
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeTable prints every schedule with a row for every meeting time of its sections.
+// The schedules are numbered from offset + 1.
+func writeTable(w io.Writer, page []models.Schedule, offset int) {
+	for i, s := range page {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "Schedule %d (%g credits)\n", offset+i+1, s.Credits)
+		for _, section := range s.Courses {
+			if len(section.Sessions) == 0 {
+				fmt.Fprintf(w, "  %-14s no fixed time\n", section.Name)
+			}
+			for _, m := range meetings(section) {
+				fmt.Fprintf(w, "  %-14s %-12s %-12s %11s  term %s\n", section.Name, m.Activity, m.Day, timeRange(m), m.Term)
+			}
+		}
+		writeDropped(w, s)
+	}
+}
+
+// gridColumn is the width of a day in the grid, wide enough for a section name. e.g. 'CPSC 221 101'
+const gridColumn = 14
+
+// gridRow is the minutes of a row in the grid.
+const gridRow = 30
+
+// writeGrid prints every schedule as a week for each of its terms, with a row every half hour.
+// The schedules are numbered from offset + 1.
+func writeGrid(w io.Writer, page []models.Schedule, offset int) {
+	for i, s := range page {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "Schedule %d (%g credits)\n", offset+i+1, s.Credits)
+		var terms models.TermSet
+		for _, section := range s.Courses {
+			for _, session := range section.Sessions {
+				terms |= session.Terms()
+			}
+		}
+		for _, term := range terms.Terms() {
+			writeWeek(w, s, models.ParseTermSet(term), term)
+		}
+		var untimed []string
+		for _, section := range s.Courses {
+			if len(section.Sessions) == 0 {
+				untimed = append(untimed, section.Name)
+			}
+		}
+		if len(untimed) != 0 {
+			fmt.Fprintf(w, "\n  no fixed time: %s\n", strings.Join(untimed, ", "))
+		}
+		writeDropped(w, s)
+	}
+}
+
+// writeWeek prints the sessions of a schedule in a term as a grid of days and times.
+// The week is Mon to Fri, and the weekend if a session is on it.
+func writeWeek(w io.Writer, s models.Schedule, term models.TermSet, termName string) {
+	type cell struct {
+		name  string
+		day   string
+		start int
+		end   int
+	}
+	var cells []cell
+	days := models.ParseWeekdays("Mon Tue Wed Thu Fri")
+	first, last := 24*60, 0
+	for _, section := range s.Courses {
+		for _, session := range section.Sessions {
+			if session.Terms()&term == 0 {
+				continue
+			}
+			start, end := models.Minutes(session.Start), models.Minutes(session.End)
+			cells = append(cells, cell{name: section.Name, day: session.Day, start: start, end: end})
+			days |= models.ParseWeekdays(session.Day)
+			if start < first {
+				first = start
+			}
+			if end > last {
+				last = end
+			}
+		}
+	}
+
+	header := fmt.Sprintf("  %-7s", "term "+termName)
+	for _, day := range days.Days() {
+		header += fmt.Sprintf("%-*s", gridColumn, day)
+	}
+	fmt.Fprintf(w, "\n%s\n", strings.TrimRight(header, " "))
+	for t := first - first%60; t < last; t += gridRow {
+		row := fmt.Sprintf("  %2d:%02d  ", t/60, t%60)
+		for _, day := range days.Days() {
+			name := ""
+			for _, c := range cells {
+				if c.day == day && c.start < t+gridRow && t < c.end {
+					name = c.name
+					break
+				}
+			}
+			row += fmt.Sprintf("%-*s", gridColumn, name)
+		}
+		fmt.Fprintln(w, strings.TrimRight(row, " "))
+	}
+}
+
+// writeDropped prints the optional courses left out of a schedule.
+func writeDropped(w io.Writer, s models.Schedule) {
+	if len(s.Dropped) != 0 {
+		fmt.Fprintf(w, "  dropped: %s\n", strings.Join(s.Dropped, ", "))
+	}
+}
+
+// writeDiagnosis prints why there are no schedules, with the unsatisfiable core and eliminations if they were explained.
+func writeDiagnosis(w io.Writer, diagnosis schedules.Diagnosis) {
+	fmt.Fprintln(w, "no schedules")
+	for _, c := range diagnosis.Courses {
+		if c.Message != "" {
+			fmt.Fprintf(w, "  %s\n", c.Message)
+		}
+	}
+	if diagnosis.Message != "" {
+		fmt.Fprintf(w, "  %s\n", diagnosis.Message)
+	}
+	if len(diagnosis.Core) != 0 {
+		fmt.Fprintln(w, "can't be in a schedule together, drop one of:")
+		for _, c := range diagnosis.Core {
+			fmt.Fprintf(w, "  %s (%s)\n", c.Course, strings.Join(c.Sections, ", "))
+		}
+	}
+	if len(diagnosis.Eliminations) != 0 {
+		fmt.Fprintln(w, "sections left out by:")
+		for _, e := range diagnosis.Eliminations {
+			fmt.Fprintf(w, "  %-14s %-14s %d\n", e.Constraint, e.Course, e.Eliminated)
+		}
+	}
+}
+
+// meetings returns the sessions of a section with the days of the sessions at the same time joined. e.g. 'Mon Wed Fri'
+func meetings(section models.CourseSection) []models.ClassSession {
+	var joined []models.ClassSession
+	for _, s := range section.Sessions {
+		found := false
+		for i, m := range joined {
+			if m.Activity == s.Activity && m.Term == s.Term && m.Start == s.Start && m.End == s.End {
+				joined[i].Day += " " + s.Day
+				found = true
+				break
+			}
+		}
+		if !found {
+			joined = append(joined, s)
+		}
+	}
+	return joined
+}
+
+// timeRange returns the time of a session. e.g. '9:00-10:30'
+func timeRange(s models.ClassSession) string {
+	return fmt.Sprintf("%d:%02d-%d:%02d", s.Start/100, s.Start%100, s.End/100, s.End%100)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/smart-cs/scheduler-backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	assert := assert.New(t)
+	var stdout, stderr bytes.Buffer
+
+	assert.Equal(0, generate([]string{"-catalog", testCatalog, "-term", "1", "-limit", "2", "CPSC 221,MATH 220"}, &stdout, &stderr))
+	assert.True(strings.HasPrefix(stdout.String(), `Schedule 1 (0 credits)
+  CPSC 221 101   Lecture      Tue Thu      14:00-15:30  term 1
+  MATH 220 101   Lecture      Mon Wed Fri  12:00-13:00  term 1
+`), stdout.String())
+	assert.Contains(stdout.String(), "Schedule 2 ")
+	assert.Contains(stdout.String(), "there are more than 2 schedules")
+
+	t.Log("the reason there are no schedules is printed")
+	stdout.Reset()
+	assert.Equal(1, generate([]string{"-catalog", testCatalog, "APSC 210"}, &stdout, &stderr))
+	assert.Equal("no schedules\n  APSC 210 has no sections in term 1-2\n", stdout.String())
+
+	t.Log("invalid requests")
+	assert.Equal(2, generate([]string{"-catalog", testCatalog, "CPSC 999"}, &stdout, &stderr))
+	assert.Contains(stderr.String(), `course "CPSC 999" doesn't exist`)
+	assert.Equal(2, generate([]string{"-catalog", testCatalog}, &stdout, &stderr))
+	assert.Equal(2, generate([]string{"-catalog", testCatalog, "-limit", "-1", "CPSC 221"}, &stdout, &stderr))
+	assert.Equal(2, generate([]string{"-catalog", testCatalog, "-format", "html", "CPSC 221"}, &stdout, &stderr))
+	assert.Equal(2, generate([]string{"-catalog", testCatalog, "-days-off", "Someday", "CPSC 221"}, &stdout, &stderr))
+	assert.Contains(stderr.String(), "days_off: invalid day")
+	assert.Equal(2, generate([]string{"-catalog", testCatalog, "-blocked", "Mon 10:00", "CPSC 221"}, &stdout, &stderr))
+	assert.Contains(stderr.String(), "blocked: ")
+	assert.Equal(2, generate([]string{"-catalog", testCatalog, "-no-classes-before", "10:00", "-no-classes-before-weight", "Inf", "CPSC 221"}, &stdout, &stderr))
+	assert.Contains(stderr.String(), "no_classes_before_weight: must be a finite non-negative number")
+}
+
+func TestGenerate_Options(t *testing.T) {
+	assert := assert.New(t)
+	var stdout, stderr bytes.Buffer
+
+	t.Log("pages are numbered like the server's")
+	assert.Equal(0, generate([]string{"-catalog", testCatalog, "-term", "1", "-offset", "1", "-limit", "1", "CPSC 221,MATH 220"}, &stdout, &stderr))
+	assert.True(strings.HasPrefix(stdout.String(), "Schedule 2 "), stdout.String())
+	assert.Contains(stdout.String(), "there are more than 2 schedules")
+
+	t.Log("optional courses which don't fit are dropped")
+	stdout.Reset()
+	assert.Equal(0, generate([]string{"-catalog", testCatalog, "-term", "1", "-limit", "1",
+		"-pinned", "MATH 220 101", "-optional", "BIOL 111", "MATH 220"}, &stdout, &stderr))
+	assert.Contains(stdout.String(), "  MATH 220 101 ")
+	assert.Contains(stdout.String(), "  dropped: BIOL 111\n")
+
+	t.Log("blocked times and preferences are applied")
+	stdout.Reset()
+	assert.Equal(0, generate([]string{"-catalog", testCatalog, "-term", "1", "-limit", "1",
+		"-blocked", "Mon Wed Fri 12:00-13:00", "-no-classes-before", "10:00", "MATH 220"}, &stdout, &stderr))
+	assert.NotContains(stdout.String(), "12:00-13:00")
+	assert.NotContains(stdout.String(), " 8:00-")
+	assert.NotContains(stdout.String(), " 9:00-")
+
+	t.Log("an explained conflict prints the courses which can't be together")
+	stdout.Reset()
+	assert.Equal(1, generate([]string{"-catalog", testCatalog, "-term", "1", "-explain",
+		"-pinned", "MATH 220 101,BIOL 111 101", "MATH 220", "BIOL 111"}, &stdout, &stderr))
+	assert.Contains(stdout.String(), "can't be in a schedule together, drop one of:\n  MATH 220 (MATH 220 101)\n  BIOL 111 (BIOL 111 101)\n")
+}
+
+func TestGenerate_Formats(t *testing.T) {
+	assert := assert.New(t)
+	var stdout, stderr bytes.Buffer
+
+	t.Log("json is the response of GET /schedules")
+	assert.Equal(0, generate([]string{"-catalog", testCatalog, "-format", "json", "-term", "1", "-limit", "1", "CPSC 221"}, &stdout, &stderr))
+	var resp struct {
+		OK      bool              `json:"OK"`
+		Body    []models.Schedule `json:"body"`
+		HasMore bool              `json:"has_more"`
+	}
+	assert.NoError(json.Unmarshal(stdout.Bytes(), &resp))
+	assert.True(resp.OK)
+	assert.True(resp.HasMore)
+	if assert.Len(resp.Body, 1) {
+		assert.Equal("CPSC 221 101", resp.Body[0].Courses[0].Name)
+	}
+
+	stdout.Reset()
+	assert.Equal(1, generate([]string{"-catalog", testCatalog, "-format", "json", "APSC 210"}, &stdout, &stderr))
+	assert.Contains(stdout.String(), `"diagnosis": {`)
+	stdout.Reset()
+	assert.Equal(2, generate([]string{"-catalog", testCatalog, "-format", "json", "CPSC 999"}, &stdout, &stderr))
+	assert.Contains(stdout.String(), `"errors": [`)
+
+	t.Log("json which can't be written isn't printed")
+	stderr.Reset()
+	assert.Equal(2, generate([]string{"-catalog", testCatalog, "-format", "json", "-term", "1", "CPSC 221"}, failingWriter{}, &stderr))
+	assert.Contains(stderr.String(), "can't write")
+
+	t.Log("grid is a week of each term")
+	stdout.Reset()
+	assert.Equal(0, generate([]string{"-catalog", testCatalog, "-format", "grid", "-term", "1", "-limit", "1", "-pinned", "MATH 220 101", "CPSC 221,MATH 220"}, &stdout, &stderr))
+	assert.Equal(`Schedule 1 (0 credits)
+
+  term 1 Mon           Tue           Wed           Thu           Fri
+  12:00  MATH 220 101                MATH 220 101                MATH 220 101
+  12:30  MATH 220 101                MATH 220 101                MATH 220 101
+  13:00
+  13:30
+  14:00                CPSC 221 101                CPSC 221 101
+  14:30                CPSC 221 101                CPSC 221 101
+  15:00                CPSC 221 101                CPSC 221 101
+
+there are more than 1 schedules
+`, stdout.String())
+
+	t.Log("sections without a fixed time are listed")
+	args := []string{"-catalog", testCatalog, "-term", "1", "-lectures-only=false", "-pinned", "BIOL 200 000,BIOL 200 T22", "BIOL 200"}
+	stdout.Reset()
+	assert.Equal(0, generate(args, &stdout, &stderr))
+	assert.Equal(`Schedule 1 (0 credits)
+  BIOL 200 000   no fixed time
+  BIOL 200 T22   Tutorial     Tue           9:00-10:00  term 1
+`, stdout.String())
+	stdout.Reset()
+	assert.Equal(0, generate(append([]string{"-format", "grid"}, args...), &stdout, &stderr))
+	assert.True(strings.HasSuffix(stdout.String(), "\n  no fixed time: BIOL 200 000\n"), stdout.String())
+}
+
+// failingWriter is a writer which can't be written to, like a closed pipe.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("can't write")
+}
@@ -2,20 +2,71 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"strconv"
+	"sort"
+	"strings"
 
-	"github.com/smart-cs/scheduler-backend/server"
+	"github.com/smart-cs/scheduler-backend/database"
 )
 
+const defaultDatabasePath = "database/coursedb.json"
+
+// command runs a subcommand with its arguments, returning the exit code.
+type command func(args []string, stdout, stderr io.Writer) int
+
+// commands are the subcommands of the binary by name.
+var commands = map[string]command{
+	"serve":        serve,
+	"validate":     validate,
+	"diff":         diff,
+	"generate":     generate,
+	"autocomplete": autocomplete,
+}
+
 func main() {
-	s := server.NewServer()
-	port, present := os.LookupEnv("PORT")
-	if present {
-		if _, err := strconv.Atoi(port); err != nil {
-			fmt.Printf("PORT environment variable is not an integer: %v\n", err)
-			return
-		}
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the subcommand named by the first argument with the other arguments.
+// Runs serve if there's no subcommand, e.g. with only flags.
+func run(args []string, stdout, stderr io.Writer) int {
+	name := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	switch name {
+	case "", "serve":
+		return serve(args, stdout, stderr)
+	case "help":
+		usage(stdout)
+		return 0
+	}
+	c, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n", name)
+		usage(stderr)
+		return 2
+	}
+	return c(args, stdout, stderr)
+}
+
+// usage lists the subcommands.
+func usage(w io.Writer) {
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(w, "usage: scheduler-backend <command> [flags] [arguments]\n\ncommands: %s\n", strings.Join(names, ", "))
+	fmt.Fprintln(w, "run a command with -h for its flags, the default command is serve")
+}
+
+// loadCatalog loads the course database as the current snapshot, printing the error if it can't be loaded.
+func loadCatalog(path string, stderr io.Writer) bool {
+	if err := database.LoadLocalDatabase(path); err != nil {
+		fmt.Fprintln(stderr, err)
+		return false
 	}
-	s.Run()
+	return true
 }
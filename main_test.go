@@ -1,15 +1,21 @@
 package main
 
 import (
+	"bytes"
+	"io/ioutil"
 	"os"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
-func runMainAndAssertExit(t *testing.T, shouldExit bool) {
+const testCatalog = "database/test-coursedb.json"
+
+func runAndAssertExit(t *testing.T, args []string, shouldExit bool) {
 	finished := make(chan bool)
 	go func() {
-		main()
+		run(args, ioutil.Discard, ioutil.Discard)
 		finished <- true
 	}()
 
@@ -17,32 +23,52 @@ func runMainAndAssertExit(t *testing.T, shouldExit bool) {
 	time.Sleep(time.Millisecond * 1500)
 	select {
 	case _ = <-finished:
-		// Channel received a message, main exited.
+		// Channel received a message, run exited.
 		if !shouldExit {
 			t.FailNow()
 		}
 		return
 	default:
-		// Channel hasn't received a message, main hasn't exited.
+		// Channel hasn't received a message, run hasn't exited.
 		if shouldExit {
 			t.FailNow()
 		}
 	}
 }
 
-func TestMain(t *testing.T) {
-	t.Log("main should block forever because it starts a server")
-	runMainAndAssertExit(t, false)
+func TestRun(t *testing.T) {
+	t.Log("serve should block forever because it starts a server")
+	runAndAssertExit(t, []string{"serve", "-catalog", testCatalog, "-port", "4320", "-addr", "localhost"}, false)
 }
 
-func TestMain_EnvPort(t *testing.T) {
-	t.Log("main with $PORT set should block forever")
+func TestRun_EnvPort(t *testing.T) {
+	t.Log("serve is the default command and should block forever with $PORT set")
 	os.Setenv("PORT", "4321")
-	runMainAndAssertExit(t, false)
+	defer os.Unsetenv("PORT")
+	runAndAssertExit(t, []string{"-catalog", testCatalog, "-log-format", "json"}, false)
 }
 
-func TestMain_BadEnvPort(t *testing.T) {
-	t.Log("main should exit if $PORT is set to a non-integer value")
+func TestRun_BadEnvPort(t *testing.T) {
+	t.Log("serve should exit if $PORT is set to a non-integer value")
 	os.Setenv("PORT", "this value is not an integer")
-	runMainAndAssertExit(t, true)
+	defer os.Unsetenv("PORT")
+	runAndAssertExit(t, []string{"-catalog", testCatalog}, true)
+}
+
+func TestRun_BadCatalog(t *testing.T) {
+	assert := assert.New(t)
+	var stderr bytes.Buffer
+	assert.Equal(1, run([]string{"serve", "-catalog", "bad/path/to/database", "-port", "4322"}, ioutil.Discard, &stderr))
+	assert.Contains(stderr.String(), "bad/path/to/database")
+	assert.Equal(1, run([]string{"serve", "-catalog", testCatalog, "-log-format", "xml"}, ioutil.Discard, &stderr))
+}
+
+func TestRun_Commands(t *testing.T) {
+	assert := assert.New(t)
+	var stdout, stderr bytes.Buffer
+	assert.Equal(0, run([]string{"help"}, &stdout, &stderr))
+	assert.Contains(stdout.String(), "autocomplete, diff, generate, serve, validate")
+	assert.Equal(2, run([]string{"nope"}, &stdout, &stderr))
+	assert.Contains(stderr.String(), `unknown command "nope"`)
+	assert.Equal(2, run([]string{"serve", "-nope"}, &stdout, &stderr))
 }
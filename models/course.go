@@ -20,12 +20,39 @@ type CourseSection struct {
 	Name string `json:"name"`
 	// List of ClassSession.
 	Sessions []ClassSession `json:"sessions"`
+	// Registration status of the section. e.g. 'Full'
+	Status SectionStatus `json:"status"`
+}
+
+// Course holds information about a course.
+type Course struct {
+	// Code of the course: <DEPARTMENT> <NUMBER>. e.g. 'CPSC 221'
+	Code       string `json:"code"`
+	Department string `json:"department"`
+	// Number of the course. e.g. '221'
+	Number string `json:"number"`
+	// Title of the course. e.g. 'Basic Algorithms and Data Structures'
+	Title string `json:"title"`
+	// Credits of the course, 0 if unknown. e.g. 4
+	Credits     float64 `json:"credits"`
+	Description string  `json:"description"`
+	// Prerequisites of the course as written in the calendar. e.g. 'One of CPSC 210, EECE 210.'
+	Prerequisites string `json:"prerequisites"`
 }
 
 // Schedule represents a schedule of courses.
 type Schedule struct {
 	// List of Course.
 	Courses []CourseSection `json:"courses"`
+	// Score of how well the schedule matches the requested preferences, higher is better.
+	Score float64 `json:"score,omitempty"`
+	// Credits is the sum of the credits of the courses in the schedule.
+	Credits float64 `json:"credits"`
+	// TermCredits are the credits of the schedule in each of its terms, a course in term 1 and 2 counts half in each.
+	// e.g. {'1': 7, '2': 4}
+	TermCredits map[string]float64 `json:"term_credits,omitempty"`
+	// Dropped are the optional courses which aren't in the schedule. e.g. ['MATH 220']
+	Dropped []string `json:"dropped,omitempty"`
 }
 
 // ActivityType is an enum, e.g. Laboratory, Lecture.
@@ -42,20 +69,138 @@ const (
 	Studio
 	// Tutorial ActivityType
 	Tutorial
+	// Discussion ActivityType
+	Discussion
+	// LabSeminar ActivityType
+	LabSeminar
+	// LectureLaboratory ActivityType, a lecture and laboratory in one section.
+	LectureLaboratory
+	// LectureSeminar ActivityType, a lecture and seminar in one section.
+	LectureSeminar
+	// LectureDiscussion ActivityType, a lecture and discussion in one section.
+	LectureDiscussion
+	// ProblemSession ActivityType
+	ProblemSession
+	// DistanceEducation ActivityType
+	DistanceEducation
+	// DirectedStudies ActivityType
+	DirectedStudies
+	// EssayReport ActivityType
+	EssayReport
+	// ExchangeProgram ActivityType
+	ExchangeProgram
+	// FieldTrip ActivityType
+	FieldTrip
+	// FlexibleLearning ActivityType
+	FlexibleLearning
+	// OptionalSection ActivityType
+	OptionalSection
+	// Practicum ActivityType
+	Practicum
+	// Project ActivityType
+	Project
+	// Rehearsal ActivityType
+	Rehearsal
+	// Research ActivityType
+	Research
+	// ReservedSection ActivityType
+	ReservedSection
+	// Thesis ActivityType
+	Thesis
+	// WaitingList ActivityType
+	WaitingList
+	// WebOrientedCourse ActivityType
+	WebOrientedCourse
+	// WorkPlacement ActivityType
+	WorkPlacement
+	// Workshop ActivityType
+	Workshop
+	// UnknownActivity is the ActivityType of activities we don't know about.
+	UnknownActivity
 )
 
+var activityTypeNames = map[ActivityType]string{
+	Laboratory:        "Laboratory",
+	Lecture:           "Lecture",
+	Seminar:           "Seminar",
+	Studio:            "Studio",
+	Tutorial:          "Tutorial",
+	Discussion:        "Discussion",
+	LabSeminar:        "Lab-Seminar",
+	LectureLaboratory: "Lecture-Laboratory",
+	LectureSeminar:    "Lecture-Seminar",
+	LectureDiscussion: "Lecture-Discussion",
+	ProblemSession:    "Problem Session",
+	DistanceEducation: "Distance Education",
+	DirectedStudies:   "Directed Studies",
+	EssayReport:       "Essay/Report",
+	ExchangeProgram:   "Exchange Program",
+	FieldTrip:         "Field Trip",
+	FlexibleLearning:  "Flexible Learning",
+	OptionalSection:   "Optional Section",
+	Practicum:         "Practicum",
+	Project:           "Project",
+	Rehearsal:         "Rehearsal",
+	Research:          "Research",
+	ReservedSection:   "Reserved Section",
+	Thesis:            "Thesis",
+	WaitingList:       "Waiting List",
+	WebOrientedCourse: "Web-Oriented Course",
+	WorkPlacement:     "Work Placement",
+	Workshop:          "Workshop",
+	UnknownActivity:   "Unknown",
+}
+
+// PrimaryActivities are the activities of the sections every schedule of a course has one of.
+var PrimaryActivities = []ActivityType{Lecture, Seminar, Studio, LectureLaboratory, LectureSeminar, LectureDiscussion}
+
+// CompanionActivities are the activities which must be taken along with a primary activity
+// when the course has sections of them, e.g. the Laboratory of a Lecture.
+var CompanionActivities = []ActivityType{Laboratory, Tutorial, Discussion, LabSeminar, ProblemSession}
+
 func (a ActivityType) String() string {
-	switch a {
-	case Laboratory:
-		return "Laboratory"
-	case Lecture:
-		return "Lecture"
-	case Seminar:
-		return "Seminar"
-	case Studio:
-		return "Studio"
-	case Tutorial:
-		return "Tutorial"
+	if name, ok := activityTypeNames[a]; ok {
+		return name
 	}
 	return "<missing String() implementation>"
 }
+
+// IsPrimary returns true if the activity is one of PrimaryActivities.
+func (a ActivityType) IsPrimary() bool {
+	return containsActivity(PrimaryActivities, a)
+}
+
+// IsCompanion returns true if the activity is one of CompanionActivities.
+func (a ActivityType) IsCompanion() bool {
+	return containsActivity(CompanionActivities, a)
+}
+
+// ParseActivityType returns the ActivityType of an activity in the catalog. e.g. 'Lab-Seminar'
+// Unknown activities are UnknownActivity.
+func ParseActivityType(activity string) ActivityType {
+	for a, name := range activityTypeNames {
+		if activity == name {
+			return a
+		}
+	}
+	return UnknownActivity
+}
+
+func containsActivity(activities []ActivityType, activity ActivityType) bool {
+	for _, a := range activities {
+		if a == activity {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsName returns true if the name is one of the names. e.g. a section in the excluded sections
+func ContainsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -8,11 +8,13 @@ func (c *CourseHelper) CombinationsNoConflict(result [][]CourseSection, sections
 	var newResult [][]CourseSection
 	for _, comb := range result {
 		for _, section := range sections {
-			// Create an array to use conflictInSections.
-			if c.conflictInSections(append(comb, section)...) {
+			// Copy the combination so new combinations don't share the same array.
+			newComb := make([]CourseSection, len(comb), len(comb)+1)
+			copy(newComb, comb)
+			newComb = append(newComb, section)
+			if c.conflictInSections(newComb...) {
 				continue
 			}
-			newComb := append(comb, section)
 			newResult = append(newResult, newComb)
 		}
 	}
@@ -34,10 +36,15 @@ func (c *CourseHelper) ConflictInSchedule(schedule Schedule) bool {
 	return c.conflictInSections(schedule.Courses...)
 }
 
+// SectionsConflict returns true if two different sections have a conflicting session.
+func (c *CourseHelper) SectionsConflict(s1, s2 CourseSection) bool {
+	return s1.Name != s2.Name && c.conflictSection(s1, s2)
+}
+
 func (c *CourseHelper) conflictInSections(sections ...CourseSection) bool {
 	for _, s1 := range sections {
 		for _, s2 := range sections {
-			if s1.Name != s2.Name && c.conflictSection(s1, s2) {
+			if c.SectionsConflict(s1, s2) {
 				return true
 			}
 		}
@@ -57,7 +64,5 @@ func (c *CourseHelper) conflictSection(s1, s2 CourseSection) bool {
 }
 
 func (c *CourseHelper) conflictSession(s1, s2 ClassSession) bool {
-	return s1.Term == s2.Term && s1.Day == s2.Day &&
-		((s1.Start <= s2.Start && s2.Start < s1.End) ||
-			(s1.Start < s2.End && s2.End <= s1.End))
+	return s1.Overlaps(s2)
 }
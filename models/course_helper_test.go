@@ -119,3 +119,58 @@ func TestConflictInSchedule(t *testing.T) {
 	})
 	assert.True(ch.ConflictInSchedule(schedule))
 }
+
+func TestConflictInSchedule_Containment(t *testing.T) {
+	assert := assert.New(t)
+	ch := models.CourseHelper{}
+
+	schedule := models.Schedule{
+		Courses: []models.CourseSection{
+			{
+				Name: "CPSC 121 L1A",
+				Sessions: []models.ClassSession{
+					{Activity: "Laboratory", Term: "1", Day: "Wed", Start: 900, End: 1200},
+				},
+			},
+			{
+				Name: "CPSC 121 T1A",
+				Sessions: []models.ClassSession{
+					{Activity: "Tutorial", Term: "1", Day: "Wed", Start: 1000, End: 1100},
+				},
+			},
+		},
+	}
+	assert.True(ch.ConflictInSchedule(schedule), "a session contained in another should conflict")
+
+	schedule.Courses[0].Sessions[0].Term = "1-2"
+	assert.True(ch.ConflictInSchedule(schedule), "a term 1-2 session should conflict with a term 1 session")
+
+	schedule.Courses[1].Sessions[0].Start = 1200
+	schedule.Courses[1].Sessions[0].End = 1300
+	assert.False(ch.ConflictInSchedule(schedule), "back to back sessions shouldn't conflict")
+}
+
+func TestSectionsConflict(t *testing.T) {
+	assert := assert.New(t)
+	ch := models.CourseHelper{}
+
+	s1 := models.CourseSection{
+		Name: "MATH 100 101",
+		Sessions: []models.ClassSession{
+			{Activity: "Lecture", Term: "1", Day: "Mon", Start: 800, End: 900},
+			{Activity: "Lecture", Term: "1", Day: "Wed", Start: 800, End: 900},
+		},
+	}
+	s2 := models.CourseSection{
+		Name: "CPSC 100 101",
+		Sessions: []models.ClassSession{
+			{Activity: "Lecture", Term: "1", Day: "Wed", Start: 830, End: 930},
+		},
+	}
+	assert.True(ch.SectionsConflict(s1, s2))
+	assert.True(ch.SectionsConflict(s2, s1))
+	assert.False(ch.SectionsConflict(s1, s1), "a section doesn't conflict with itself")
+
+	s2.Sessions[0].Day = "Tue"
+	assert.False(ch.SectionsConflict(s1, s2))
+}
@@ -19,6 +19,17 @@ func TestActivityType(t *testing.T) {
 		{models.Seminar, "Seminar"},
 		{models.Studio, "Studio"},
 		{models.Tutorial, "Tutorial"},
+		{models.Discussion, "Discussion"},
+		{models.LabSeminar, "Lab-Seminar"},
+		{models.DistanceEducation, "Distance Education"},
+		{models.DirectedStudies, "Directed Studies"},
+		{models.FieldTrip, "Field Trip"},
+		{models.FlexibleLearning, "Flexible Learning"},
+		{models.EssayReport, "Essay/Report"},
+		{models.ExchangeProgram, "Exchange Program"},
+		{models.Thesis, "Thesis"},
+		{models.WaitingList, "Waiting List"},
+		{models.UnknownActivity, "Unknown"},
 	}
 
 	for _, item := range table {
@@ -31,3 +42,36 @@ func TestActivityType(t *testing.T) {
 		)
 	}
 }
+
+func TestParseActivityType(t *testing.T) {
+	assert := assert.New(t)
+	for a := models.Laboratory; a < models.UnknownActivity; a++ {
+		assert.Equal(a, models.ParseActivityType(a.String()))
+	}
+	assert.Equal(models.UnknownActivity, models.ParseActivityType(""))
+	assert.Equal(models.UnknownActivity, models.ParseActivityType("lecture"))
+	assert.Equal(models.UnknownActivity, models.ParseActivityType("Séance"))
+}
+
+func TestActivityTypeCompanions(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(models.Lecture.IsPrimary())
+	assert.True(models.LectureLaboratory.IsPrimary())
+	assert.False(models.Lecture.IsCompanion())
+
+	assert.True(models.Laboratory.IsCompanion())
+	assert.True(models.Tutorial.IsCompanion())
+	assert.True(models.Discussion.IsCompanion())
+	assert.False(models.Discussion.IsPrimary())
+
+	for _, a := range []models.ActivityType{models.WaitingList, models.Thesis, models.UnknownActivity} {
+		assert.Falsef(a.IsPrimary() || a.IsCompanion(), "%v shouldn't be scheduled", a)
+	}
+}
+
+func TestContainsName(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(models.ContainsName([]string{"CPSC 221 101", "CPSC 221 102"}, "CPSC 221 102"))
+	assert.False(models.ContainsName([]string{"CPSC 221 101"}, "CPSC 221"))
+	assert.False(models.ContainsName(nil, "CPSC 221 101"))
+}
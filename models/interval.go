@@ -0,0 +1,152 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TermSet is a set of terms, e.g. term '1-2' is the set of terms 1 and 2.
+type TermSet uint8
+
+const (
+	// Term1 is the winter term 1.
+	Term1 TermSet = 1 << iota
+	// Term2 is the winter term 2.
+	Term2
+	// TermA is the summer term A.
+	TermA
+	// TermB is the summer term B.
+	TermB
+	// TermC is the summer term C.
+	TermC
+	// TermD is the summer term D.
+	TermD
+)
+
+// ParseTermSet returns the set of terms of a term string. e.g. '1', '2', '1-2'
+// Unknown terms are an empty set.
+func ParseTermSet(term string) TermSet {
+	switch term {
+	case "1":
+		return Term1
+	case "2":
+		return Term2
+	case "1-2":
+		return Term1 | Term2
+	case "A":
+		return TermA
+	case "B":
+		return TermB
+	case "C":
+		return TermC
+	case "D":
+		return TermD
+	}
+	return 0
+}
+
+var termNames = []string{"1", "2", "A", "B", "C", "D"}
+
+// Terms returns the terms in the set in order. e.g. ['1', '2']
+func (t TermSet) Terms() []string {
+	terms := []string{}
+	for i, name := range termNames {
+		if t&(1<<uint(i)) != 0 {
+			terms = append(terms, name)
+		}
+	}
+	return terms
+}
+
+// Weekdays is a set of days of the week.
+type Weekdays uint8
+
+// Days of the week.
+const (
+	Monday Weekdays = 1 << iota
+	Tuesday
+	Wednesday
+	Thursday
+	Friday
+	Saturday
+	Sunday
+)
+
+var weekdayNames = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+// ParseWeekdays returns the set of days in a string of space separated days. e.g. 'Mon Wed Fri'
+// Unknown days are ignored.
+func ParseWeekdays(days string) Weekdays {
+	// Fast path for a single day, which is what sessions usually hold.
+	for i, name := range weekdayNames {
+		if days == name {
+			return 1 << uint(i)
+		}
+	}
+
+	var set Weekdays
+	for _, day := range strings.Fields(days) {
+		for i, name := range weekdayNames {
+			if day == name {
+				set |= 1 << uint(i)
+			}
+		}
+	}
+	return set
+}
+
+// Days returns the days in the set in weekday order. e.g. ['Mon', 'Wed', 'Fri']
+func (w Weekdays) Days() []string {
+	var days []string
+	for i, name := range weekdayNames {
+		if w&(1<<uint(i)) != 0 {
+			days = append(days, name)
+		}
+	}
+	return days
+}
+
+// Minutes converts a 24 hour time representation to minutes since midnight. e.g. 1230 -> 750
+func Minutes(hhmm int) int {
+	return hhmm/100*60 + hhmm%100
+}
+
+// validTime returns true if the 24 hour representation is a time of day from 0000 to 2400.
+func validTime(hhmm int) bool {
+	return hhmm >= 0 && hhmm <= 2400 && hhmm%100 < 60
+}
+
+// ParseTime parses a time in the format HH:MM or HHMM to its 24 hour representation. e.g. '9:30' -> 930
+func ParseTime(time string) (int, error) {
+	hhmm, err := strconv.Atoi(strings.Replace(time, ":", "", 1))
+	if err != nil || !validTime(hhmm) {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", time)
+	}
+	return hhmm, nil
+}
+
+// Terms returns the set of terms the session is in.
+func (s ClassSession) Terms() TermSet {
+	return ParseTermSet(s.Term)
+}
+
+// Weekdays returns the set of days the session is on.
+func (s ClassSession) Weekdays() Weekdays {
+	return ParseWeekdays(s.Day)
+}
+
+// MinuteRange returns the half-open range [start, end) of the session in minutes since midnight.
+func (s ClassSession) MinuteRange() (start, end int) {
+	return Minutes(s.Start), Minutes(s.End)
+}
+
+// Overlaps returns true if both sessions happen at the same time on the same day in the same term.
+func (s ClassSession) Overlaps(other ClassSession) bool {
+	if s.Terms()&other.Terms() == 0 || s.Weekdays()&other.Weekdays() == 0 {
+		return false
+	}
+	start1, end1 := s.MinuteRange()
+	start2, end2 := other.MinuteRange()
+	return start1 < end1 && start2 < end2 && start1 < end2 && start2 < end1
+}
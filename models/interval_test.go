@@ -0,0 +1,154 @@
+package models_test
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/smart-cs/scheduler-backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTermSet(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(models.Term1, models.ParseTermSet("1"))
+	assert.Equal(models.Term2, models.ParseTermSet("2"))
+	assert.Equal(models.Term1|models.Term2, models.ParseTermSet("1-2"))
+	assert.Equal(models.TermA, models.ParseTermSet("A"))
+	assert.Zero(models.ParseTermSet(""))
+	assert.Zero(models.ParseTermSet("3"))
+	assert.Equal([]string{"1", "2"}, models.ParseTermSet("1-2").Terms())
+	assert.Equal([]string{"2", "C"}, (models.TermC | models.Term2).Terms())
+	assert.Empty(models.TermSet(0).Terms())
+}
+
+func TestParseWeekdays(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(models.Monday, models.ParseWeekdays("Mon"))
+	assert.Equal(models.Monday|models.Wednesday|models.Friday, models.ParseWeekdays("Mon Wed Fri"))
+	assert.Equal(models.Tuesday|models.Thursday, models.ParseWeekdays(" Tue  Thu bogus"))
+	assert.Zero(models.ParseWeekdays(""))
+	assert.Equal([]string{"Mon", "Wed", "Fri"}, models.ParseWeekdays("Fri Mon Wed").Days())
+}
+
+func TestMinutes(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(0, models.Minutes(0))
+	assert.Equal(750, models.Minutes(1230))
+	assert.Equal(9*60+5, models.Minutes(905))
+}
+
+func TestParseTime(t *testing.T) {
+	assert := assert.New(t)
+	for in, out := range map[string]int{"9:30": 930, "09:30": 930, "0930": 930, "23:59": 2359, "0:00": 0} {
+		hhmm, err := models.ParseTime(in)
+		assert.NoError(err)
+		assert.Equal(out, hhmm)
+	}
+	for _, in := range []string{"", "9:60", "25:00", "noon", "-1:00", "9:30:00"} {
+		_, err := models.ParseTime(in)
+		assert.Errorf(err, "%q should be an invalid time", in)
+	}
+}
+
+func TestClassSessionOverlaps(t *testing.T) {
+	assert := assert.New(t)
+	session := func(term, day string, start, end int) models.ClassSession {
+		return models.ClassSession{Term: term, Day: day, Start: start, End: end}
+	}
+	table := []struct {
+		s1, s2   models.ClassSession
+		overlaps bool
+	}{
+		{session("1", "Mon", 900, 1000), session("1", "Mon", 900, 1000), true},
+		{session("1", "Mon", 900, 1000), session("1", "Mon", 930, 1030), true},
+		{session("1", "Mon", 900, 1200), session("1", "Mon", 1000, 1100), true},
+		{session("1", "Mon", 900, 1000), session("1", "Mon", 1000, 1100), false},
+		{session("1", "Mon", 900, 1000), session("1", "Tue", 900, 1000), false},
+		{session("1", "Mon", 900, 1000), session("2", "Mon", 900, 1000), false},
+		{session("1-2", "Mon", 900, 1000), session("1", "Mon", 930, 1000), true},
+		{session("1-2", "Mon", 900, 1000), session("2", "Mon", 930, 1000), true},
+		{session("1", "Mon Wed Fri", 900, 1000), session("1", "Wed", 930, 1000), true},
+		{session("1", "", 900, 1000), session("1", "Mon", 900, 1000), false},
+		{session("1", "Mon", 1000, 1000), session("1", "Mon", 900, 1100), false},
+	}
+	for _, item := range table {
+		assert.Equalf(item.overlaps, item.s1.Overlaps(item.s2), "%v overlaps %v", item.s1, item.s2)
+		assert.Equalf(item.overlaps, item.s2.Overlaps(item.s1), "%v overlaps %v", item.s2, item.s1)
+	}
+}
+
+// randomSession generates arbitrary sessions for property based tests.
+type randomSession struct {
+	models.ClassSession
+}
+
+func (randomSession) Generate(r *rand.Rand, size int) reflect.Value {
+	terms := []string{"1", "2", "1-2", "A", ""}
+	var days []string
+	for _, day := range []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"} {
+		if r.Intn(3) == 0 {
+			days = append(days, day)
+		}
+	}
+	randomTime := func() int {
+		return (7+r.Intn(14))*100 + r.Intn(4)*15
+	}
+	return reflect.ValueOf(randomSession{models.ClassSession{
+		Term:  terms[r.Intn(len(terms))],
+		Day:   strings.Join(days, " "),
+		Start: randomTime(),
+		End:   randomTime(),
+	}})
+}
+
+type slot struct {
+	term   models.TermSet
+	day    models.Weekdays
+	minute int
+}
+
+// minuteGrid returns every (term, day, minute) slot the session occupies.
+func minuteGrid(s models.ClassSession) map[slot]bool {
+	grid := make(map[slot]bool)
+	start, end := s.MinuteRange()
+	for term := models.Term1; term <= models.TermD; term <<= 1 {
+		for day := models.Monday; day <= models.Sunday; day <<= 1 {
+			if s.Terms()&term == 0 || s.Weekdays()&day == 0 {
+				continue
+			}
+			for minute := start; minute < end; minute++ {
+				grid[slot{term, day, minute}] = true
+			}
+		}
+	}
+	return grid
+}
+
+func TestClassSessionOverlaps_MinuteGridOracle(t *testing.T) {
+	overlapsLikeGrid := func(s1, s2 randomSession) bool {
+		grid := minuteGrid(s1.ClassSession)
+		shared := false
+		for slot := range minuteGrid(s2.ClassSession) {
+			if grid[slot] {
+				shared = true
+				break
+			}
+		}
+		return s1.Overlaps(s2.ClassSession) == shared
+	}
+	if err := quick.Check(overlapsLikeGrid, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClassSessionOverlaps_Symmetric(t *testing.T) {
+	symmetric := func(s1, s2 randomSession) bool {
+		return s1.Overlaps(s2.ClassSession) == s2.Overlaps(s1.ClassSession)
+	}
+	if err := quick.Check(symmetric, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Error(err)
+	}
+}
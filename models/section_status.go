@@ -0,0 +1,96 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SectionStatus is an enum of the registration status of a section, e.g. Available, Full.
+type SectionStatus int
+
+const (
+	// Available SectionStatus, the section has seats left.
+	Available SectionStatus = iota
+	// Full SectionStatus
+	Full
+	// Blocked SectionStatus
+	Blocked
+	// Restricted SectionStatus, seats are reserved for some students.
+	Restricted
+	// STT SectionStatus, the section is part of a Standard Timetable.
+	STT
+	// Unreleased SectionStatus
+	Unreleased
+	// TempUnavailable SectionStatus
+	TempUnavailable
+	// Cancelled SectionStatus
+	Cancelled
+	// UnknownStatus is the SectionStatus of statuses we don't know about.
+	UnknownStatus
+)
+
+var sectionStatusNames = map[SectionStatus]string{
+	Available:       "Available",
+	Full:            "Full",
+	Blocked:         "Blocked",
+	Restricted:      "Restricted",
+	STT:             "STT",
+	Unreleased:      "Unreleased",
+	TempUnavailable: "Temp. Unavailable",
+	Cancelled:       "Cancelled",
+	UnknownStatus:   "Unknown",
+}
+
+func (s SectionStatus) String() string {
+	if name, ok := sectionStatusNames[s]; ok {
+		return name
+	}
+	return "<missing String() implementation>"
+}
+
+// ParseSectionStatus returns the SectionStatus of a status in the catalog, case insensitive.
+// An empty status is Available and unknown statuses are UnknownStatus.
+func ParseSectionStatus(status string) SectionStatus {
+	if status == "" {
+		return Available
+	}
+	for s, name := range sectionStatusNames {
+		if s != UnknownStatus && strings.EqualFold(status, name) {
+			return s
+		}
+	}
+	return UnknownStatus
+}
+
+// ParseSectionStatuses parses comma separated statuses. e.g. 'Full,Cancelled'
+func ParseSectionStatuses(statuses string) ([]SectionStatus, error) {
+	parsed := []SectionStatus{}
+	for _, status := range strings.Split(statuses, ",") {
+		status = strings.TrimSpace(status)
+		if status == "" {
+			continue
+		}
+		s := ParseSectionStatus(status)
+		if s == UnknownStatus {
+			return nil, fmt.Errorf("invalid status %q", status)
+		}
+		parsed = append(parsed, s)
+	}
+	return parsed, nil
+}
+
+// MarshalJSON marshals the status as its name.
+func (s SectionStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON unmarshals a status from its name.
+func (s *SectionStatus) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err != nil {
+		return err
+	}
+	*s = ParseSectionStatus(name)
+	return nil
+}
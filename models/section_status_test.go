@@ -0,0 +1,64 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/smart-cs/scheduler-backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSectionStatus(t *testing.T) {
+	assert := assert.New(t)
+	table := []struct {
+		in  string
+		out models.SectionStatus
+	}{
+		{"", models.Available},
+		{"Available", models.Available},
+		{"Full", models.Full},
+		{"full", models.Full},
+		{"Blocked", models.Blocked},
+		{"Restricted", models.Restricted},
+		{"STT", models.STT},
+		{"Unreleased", models.Unreleased},
+		{"Temp. Unavailable", models.TempUnavailable},
+		{"Cancelled", models.Cancelled},
+		{"Sold out", models.UnknownStatus},
+	}
+	for _, item := range table {
+		assert.Equalf(item.out, models.ParseSectionStatus(item.in), "status %q should be %v", item.in, item.out)
+		if item.out != models.UnknownStatus {
+			assert.Equal(item.out, models.ParseSectionStatus(item.out.String()))
+		}
+	}
+}
+
+func TestParseSectionStatuses(t *testing.T) {
+	assert := assert.New(t)
+
+	statuses, err := models.ParseSectionStatuses("Full, Cancelled")
+	assert.NoError(err)
+	assert.Equal([]models.SectionStatus{models.Full, models.Cancelled}, statuses)
+
+	statuses, err = models.ParseSectionStatuses("")
+	assert.NoError(err)
+	assert.NotNil(statuses)
+	assert.Empty(statuses)
+
+	_, err = models.ParseSectionStatuses("Full,Sold out")
+	assert.Error(err)
+}
+
+func TestSectionStatusJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	b, err := json.Marshal(models.CourseSection{Name: "CPSC 121 101", Status: models.TempUnavailable})
+	assert.NoError(err)
+	assert.JSONEq(`{"name":"CPSC 121 101","sessions":null,"status":"Temp. Unavailable"}`, string(b))
+
+	var section models.CourseSection
+	assert.NoError(json.Unmarshal(b, &section))
+	assert.Equal(models.TempUnavailable, section.Status)
+	assert.Error(json.Unmarshal([]byte(`{"status":1}`), &section))
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// TimeBlock is a window of time a student can't take classes in. e.g. work on Tue Thu 13:00-17:00
+type TimeBlock struct {
+	// Term '1' or '2' or '1-2'.
+	Term string `json:"term"`
+	// Days of the week. e.g. 'Tue Thu'
+	Day string `json:"day"`
+	// Start time of the block (24 hour representation). e.g. 1300
+	Start int `json:"start"`
+	// End time of the block (24 hour representation). e.g. 1700
+	End int `json:"end"`
+}
+
+// Section returns the block as a CourseSection to check it for conflicts like a class.
+func (b TimeBlock) Section() CourseSection {
+	return CourseSection{
+		Name: fmt.Sprintf("Blocked %s %s %04d-%04d", b.Term, b.Day, b.Start, b.End),
+		Sessions: []ClassSession{
+			{
+				Activity: "Blocked",
+				Term:     b.Term,
+				Day:      b.Day,
+				Start:    b.Start,
+				End:      b.End,
+			},
+		},
+	}
+}
+
+// Validate returns an error if the block has an invalid term, day or time, or doesn't cover any time.
+func (b TimeBlock) Validate() error {
+	if ParseTermSet(b.Term) == 0 {
+		return fmt.Errorf("invalid term %q, expected 1, 2 or 1-2", b.Term)
+	}
+	for _, day := range strings.Fields(b.Day) {
+		if ParseWeekdays(day) == 0 {
+			return fmt.Errorf("invalid day %q, expected one of Mon, Tue, Wed, Thu, Fri, Sat, Sun", day)
+		}
+	}
+	if ParseWeekdays(b.Day) == 0 {
+		return fmt.Errorf("no days given")
+	}
+	for _, t := range []int{b.Start, b.End} {
+		if !validTime(t) {
+			return fmt.Errorf("invalid time %04d, expected HHMM from 0000 to 2400", t)
+		}
+	}
+	if b.Start >= b.End {
+		return fmt.Errorf("start time %04d must be before end time %04d", b.Start, b.End)
+	}
+	return nil
+}
+
+// ParseTimeBlock parses a block in the format '[TERM:]DAYS HH:MM-HH:MM', the term defaults to 1-2.
+// e.g. 'Tue Thu 13:00-17:00', '1:Mon 9:00-12:00'
+func ParseTimeBlock(block string) (TimeBlock, error) {
+	b := TimeBlock{Term: "1-2"}
+	for _, term := range []string{"1-2", "1", "2", "A", "B", "C", "D"} {
+		if strings.HasPrefix(block, term+":") {
+			b.Term = term
+			block = block[len(term)+1:]
+			break
+		}
+	}
+
+	fields := strings.Fields(block)
+	if len(fields) < 2 {
+		return b, fmt.Errorf("invalid time block %q, expected [TERM:]DAYS HH:MM-HH:MM", block)
+	}
+	times := strings.Split(fields[len(fields)-1], "-")
+	if len(times) != 2 {
+		return b, fmt.Errorf("invalid time range %q, expected HH:MM-HH:MM", fields[len(fields)-1])
+	}
+	var err error
+	if b.Start, err = ParseTime(times[0]); err != nil {
+		return b, err
+	}
+	if b.End, err = ParseTime(times[1]); err != nil {
+		return b, err
+	}
+	b.Day = strings.Join(fields[:len(fields)-1], " ")
+	return b, b.Validate()
+}
+
+// ParseTimeBlocks parses comma separated blocks in the format of ParseTimeBlock,
+// or a JSON array of TimeBlock.
+func ParseTimeBlocks(blocks string) ([]TimeBlock, error) {
+	blocks = strings.TrimSpace(blocks)
+	if strings.HasPrefix(blocks, "[") {
+		var parsed []TimeBlock
+		if err := json.Unmarshal([]byte(blocks), &parsed); err != nil {
+			return nil, fmt.Errorf("invalid time blocks: %s", err.Error())
+		}
+		for _, b := range parsed {
+			if err := b.Validate(); err != nil {
+				return nil, err
+			}
+		}
+		return parsed, nil
+	}
+
+	var parsed []TimeBlock
+	for _, block := range strings.Split(blocks, ",") {
+		if strings.TrimSpace(block) == "" {
+			continue
+		}
+		b, err := ParseTimeBlock(strings.TrimSpace(block))
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, b)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,79 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/smart-cs/scheduler-backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTimeBlock(t *testing.T) {
+	assert := assert.New(t)
+	table := []struct {
+		in  string
+		out models.TimeBlock
+	}{
+		{"Tue Thu 13:00-17:00", models.TimeBlock{Term: "1-2", Day: "Tue Thu", Start: 1300, End: 1700}},
+		{"1:Mon 9:00-12:30", models.TimeBlock{Term: "1", Day: "Mon", Start: 900, End: 1230}},
+		{"1-2:Fri 0800-0900", models.TimeBlock{Term: "1-2", Day: "Fri", Start: 800, End: 900}},
+		{"2:Sat Sun 10:00-16:00", models.TimeBlock{Term: "2", Day: "Sat Sun", Start: 1000, End: 1600}},
+	}
+	for _, item := range table {
+		b, err := models.ParseTimeBlock(item.in)
+		assert.NoError(err)
+		assert.Equal(item.out, b)
+	}
+
+	for _, in := range []string{"", "Tue", "13:00-17:00", "Tue 13:00", "Tue 17:00-13:00", "Caturday 13:00-17:00", "3:Tue 13:00-17:00", "Tue 13:00-25:00"} {
+		_, err := models.ParseTimeBlock(in)
+		assert.Errorf(err, "%q should be an invalid time block", in)
+	}
+}
+
+func TestParseTimeBlocks(t *testing.T) {
+	assert := assert.New(t)
+	expected := []models.TimeBlock{
+		{Term: "1-2", Day: "Tue", Start: 1300, End: 1700},
+		{Term: "1", Day: "Thu", Start: 1300, End: 1700},
+	}
+
+	blocks, err := models.ParseTimeBlocks("Tue 13:00-17:00, 1:Thu 13:00-17:00")
+	assert.NoError(err)
+	assert.Equal(expected, blocks)
+
+	blocks, err = models.ParseTimeBlocks(`[{"term":"1-2","day":"Tue","start":1300,"end":1700},{"term":"1","day":"Thu","start":1300,"end":1700}]`)
+	assert.NoError(err)
+	assert.Equal(expected, blocks)
+
+	blocks, err = models.ParseTimeBlocks("")
+	assert.NoError(err)
+	assert.Empty(blocks)
+
+	_, err = models.ParseTimeBlocks("Tue 13:00-17:00,Thu")
+	assert.Error(err)
+	_, err = models.ParseTimeBlocks(`[{"term":"1","day":"Thu","start":1700,"end":1300}]`)
+	assert.Error(err)
+	_, err = models.ParseTimeBlocks(`[{"term":"1","day":"Thu","start":1299,"end":9999}]`)
+	assert.Error(err)
+	_, err = models.ParseTimeBlocks(`[{"term":"1","day":"Thu","start":-100,"end":1300}]`)
+	assert.Error(err)
+	_, err = models.ParseTimeBlocks(`[{"term":"1"`)
+	assert.Error(err)
+}
+
+func TestTimeBlockSection(t *testing.T) {
+	assert := assert.New(t)
+	ch := models.CourseHelper{}
+	block := models.TimeBlock{Term: "1-2", Day: "Tue Thu", Start: 1300, End: 1700}
+
+	lecture := models.CourseSection{
+		Name: "CPSC 121 101",
+		Sessions: []models.ClassSession{
+			{Activity: "Lecture", Term: "1", Day: "Thu", Start: 1600, End: 1730},
+		},
+	}
+	assert.True(ch.SectionsConflict(block.Section(), lecture))
+
+	lecture.Sessions[0].Start = 1700
+	assert.False(ch.SectionsConflict(block.Section(), lecture))
+}
@@ -1,6 +1,7 @@
 package schedules
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/derekparker/trie"
@@ -10,21 +11,69 @@ import (
 // AutoCompleter finds courses with certain prefixes.
 type AutoCompleter interface {
 	CoursesWithPrefix(prefix string) []string
+
+	// Complete returns the courses completing the text from the best to the worst, at most limit of them
+	// or all of them if limit is 0. If no course starts with the text, the courses found by a CourseSearcher are returned.
+	Complete(text string, limit int) []Completion
+}
+
+// Completion is a course completing a text.
+type Completion struct {
+	// Code of the course. e.g. 'CPSC 221'
+	Code       string `json:"code"`
+	Department string `json:"department"`
+	// Number of the course. e.g. '221'
+	Number string `json:"number"`
+	// Terms the course has sections in. e.g. ['1', '2']
+	Terms []string `json:"terms"`
 }
 
 // DefaultAutoCompleter implements AutoCompleter.
 type DefaultAutoCompleter struct {
 	Courses trie.Trie
+	// Popularity ranks the courses requested more often first, ignored if nil.
+	Popularity *Popularity
+
+	completions map[string]Completion
+	searcher    CourseSearcher
+}
+
+// NewAutoCompleter constructs an AutoCompleter of the current database.
+func NewAutoCompleter() (AutoCompleter, error) {
+	return NewPopularAutoCompleter(nil)
+}
+
+// NewPopularAutoCompleter constructs an AutoCompleter of the current database ranking the most popular courses first.
+func NewPopularAutoCompleter(popularity *Popularity) (AutoCompleter, error) {
+	catalog, err := database.CourseCatalog()
+	if err != nil {
+		return nil, err
+	}
+	return NewCatalogAutoCompleter(catalog, popularity), nil
 }
 
-// NewAutoCompleter constructs an AutoCompleter.
-func NewAutoCompleter() AutoCompleter {
+// NewCatalogAutoCompleter constructs an AutoCompleter of the courses in the catalog ranking the most popular courses first.
+func NewCatalogAutoCompleter(catalog *database.Catalog, popularity *Popularity) AutoCompleter {
 	t := trie.New()
-	for _, d := range database.ValidCourses() {
+	completions := make(map[string]Completion)
+	for _, d := range catalog.CourseNames() {
 		t.Add(d, nil)
+		completion := Completion{
+			Code:   d,
+			Number: strings.TrimPrefix(d, courseDepartment(d)+" "),
+			Terms:  []string{},
+		}
+		if course := catalog.Course(d); course != nil {
+			completion.Department = course.Department
+			completion.Terms = course.Terms().Terms()
+		}
+		completions[d] = completion
 	}
 	return &DefaultAutoCompleter{
-		Courses: *t,
+		Courses:     *t,
+		Popularity:  popularity,
+		completions: completions,
+		searcher:    NewCatalogCourseSearcher(catalog),
 	}
 }
 
@@ -32,3 +81,51 @@ func NewAutoCompleter() AutoCompleter {
 func (d *DefaultAutoCompleter) CoursesWithPrefix(prefix string) []string {
 	return d.Courses.PrefixSearch(strings.ToUpper(prefix))
 }
+
+// Complete returns the courses completing the text. Exact matches go first, then the most popular courses,
+// then the shortest courses and then in lexicographic order.
+func (d *DefaultAutoCompleter) Complete(text string, limit int) []Completion {
+	courses := d.CoursesWithPrefix(text)
+	if len(courses) == 0 {
+		// Nothing starts with the text, it may be spelled differently. e.g. 'cpsc221'
+		for _, result := range d.searcher.Search(text, limit) {
+			courses = append(courses, result.Course)
+		}
+	} else {
+		exact := strings.ToUpper(text)
+		popularity := make(map[string]int, len(courses))
+		for _, c := range courses {
+			popularity[c] = d.Popularity.Count(c)
+		}
+		sort.Slice(courses, func(i, j int) bool {
+			a, b := courses[i], courses[j]
+			if (a == exact) != (b == exact) {
+				return a == exact
+			}
+			if popularity[a] != popularity[b] {
+				return popularity[a] > popularity[b]
+			}
+			if len(a) != len(b) {
+				return len(a) < len(b)
+			}
+			return a < b
+		})
+	}
+	if limit > 0 && len(courses) > limit {
+		courses = courses[:limit]
+	}
+
+	completions := make([]Completion, 0, len(courses))
+	for _, c := range courses {
+		completions = append(completions, d.completions[c])
+	}
+	return completions
+}
+
+// courseDepartment returns the department of a course name. e.g. 'CPSC 221' -> 'CPSC'
+func courseDepartment(course string) string {
+	if i := strings.Index(course, " "); i != -1 {
+		return course[:i]
+	}
+	return course
+}
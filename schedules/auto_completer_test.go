@@ -17,7 +17,8 @@ func TestNewAutoCompleter(t *testing.T) {
 	setupAutocompleterTests()
 	assert := assert.New(t)
 
-	ac := schedules.NewAutoCompleter()
+	ac, err := schedules.NewAutoCompleter()
+	assert.NoError(err)
 	result := ac.CoursesWithPrefix("CPSC")
 	assert.Contains(result, "CPSC 110")
 	assert.Contains(result, "CPSC 121")
@@ -42,3 +43,55 @@ func TestNewAutoCompleter(t *testing.T) {
 		)
 	}
 }
+
+func completionCodes(completions []schedules.Completion) []string {
+	var codes []string
+	for _, c := range completions {
+		codes = append(codes, c.Code)
+	}
+	return codes
+}
+
+func TestAutoCompleter_Complete(t *testing.T) {
+	setupAutocompleterTests()
+	assert := assert.New(t)
+	ac, err := schedules.NewAutoCompleter()
+	assert.NoError(err)
+
+	assert.Equal([]schedules.Completion{
+		{Code: "CPSC 121", Department: "CPSC", Number: "121", Terms: []string{"1", "2"}},
+	}, ac.Complete("cpsc 121", 0))
+
+	t.Log("exact matches go first, then the shortest courses in lexicographic order")
+	assert.Equal([]string{"ARCH 404", "ARCH 404A", "ARCH 404B"}, completionCodes(ac.Complete("arch 404", 3)))
+	assert.Equal([]string{"CPSC 100", "CPSC 103", "CPSC 110"}, completionCodes(ac.Complete("CPSC", 3)))
+	assert.Len(ac.Complete("C", 0), len(ac.CoursesWithPrefix("C")))
+	assert.Equal(completionCodes(ac.Complete("C", 5)), completionCodes(ac.Complete("C", 5)), "the order should be stable")
+
+	t.Log("falls back to searching when no course starts with the text")
+	assert.Equal("CPSC 221", ac.Complete("CSPC 221", 1)[0].Code)
+	assert.NotNil(ac.Complete("ZZZZZZZZZZZZ", 0))
+	assert.Empty(ac.Complete("ZZZZZZZZZZZZ", 0))
+}
+
+func TestAutoCompleter_Popularity(t *testing.T) {
+	setupAutocompleterTests()
+	assert := assert.New(t)
+	popularity := schedules.NewPopularity()
+	ac, err := schedules.NewPopularAutoCompleter(popularity)
+	assert.NoError(err)
+
+	popularity.Record("CPSC 221", "CPSC 221", "CPSC 110")
+	assert.Equal([]string{"CPSC 221", "CPSC 110", "CPSC 100"}, completionCodes(ac.Complete("CPSC", 3)))
+
+	popularity.Record("ARCH 404B")
+	assert.Equal([]string{"ARCH 404", "ARCH 404B", "ARCH 404A"}, completionCodes(ac.Complete("ARCH 404", 0)), "exact matches go first")
+}
+
+func TestAutoCompleter_Catalog(t *testing.T) {
+	assert := assert.New(t)
+	catalog := database.NewCatalog(database.CourseDatabase{"CPSC": {"CPSC 999": {}}})
+	ac := schedules.NewCatalogAutoCompleter(catalog, nil)
+	assert.Equal([]string{"CPSC 999"}, ac.CoursesWithPrefix("CPSC"))
+	assert.Equal("CPSC 999", ac.Complete("CSPC 999", 0)[0].Code)
+}
@@ -0,0 +1,247 @@
+package schedules
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/smart-cs/scheduler-backend/database"
+)
+
+// Matches of SearchResult, from the best to the worst.
+const (
+	// MatchExact is a course code equal to the query. e.g. 'cpsc-221' for 'CPSC 221'
+	MatchExact = "exact"
+	// MatchPrefix is a course code starting with the query. e.g. 'cpsc2' for 'CPSC 221'
+	MatchPrefix = "prefix"
+	// MatchNumber is a course number starting with the query. e.g. '221' for 'CPSC 221'
+	MatchNumber = "number"
+	// MatchTitle is a course title with words starting with every word of the query. e.g. 'data struct'
+	MatchTitle = "title"
+	// MatchFuzzy is a course code a few typos away from the query. e.g. 'CSPC 221' for 'CPSC 221'
+	MatchFuzzy = "fuzzy"
+)
+
+var matchRanks = map[string]int{
+	MatchExact:  0,
+	MatchPrefix: 1,
+	MatchNumber: 2,
+	MatchTitle:  3,
+	MatchFuzzy:  4,
+}
+
+// CourseSearcher finds courses matching a query, ignoring case, spacing and punctuation and tolerating typos.
+type CourseSearcher interface {
+	// Search returns the courses matching the query from the best to the worst match,
+	// at most limit of them or all of them if limit is 0.
+	Search(query string, limit int) []SearchResult
+}
+
+// SearchResult is a course matching a search query.
+type SearchResult struct {
+	Course string `json:"course"`
+	// Match is how the course matched, one of the Match constants. e.g. 'fuzzy'
+	Match string `json:"match"`
+	// Distance is the number of typos in the query for fuzzy matches.
+	Distance int `json:"distance,omitempty"`
+}
+
+// DefaultCourseSearcher implements CourseSearcher.
+type DefaultCourseSearcher struct {
+	courses []searchEntry
+}
+
+type searchEntry struct {
+	code string
+	// normalized code. e.g. 'CPSC221'
+	normalized string
+	// normalized number of the course. e.g. '221'
+	number string
+	// words of the title in lower case.
+	title []string
+}
+
+// NewCourseSearcher constructs a CourseSearcher of the courses in the database, with their titles if they have metadata.
+func NewCourseSearcher() (CourseSearcher, error) {
+	catalog, err := database.CourseCatalog()
+	if err != nil {
+		return nil, err
+	}
+	return NewCatalogCourseSearcher(catalog), nil
+}
+
+// NewCatalogCourseSearcher constructs a CourseSearcher of the courses in the catalog, with their titles if they have metadata.
+func NewCatalogCourseSearcher(catalog *database.Catalog) CourseSearcher {
+	courses := catalog.CourseNames()
+	titles := make(map[string]string)
+	for _, c := range courses {
+		if info, ok := catalog.CourseInfo(c); ok && info.Title != "" {
+			titles[c] = info.Title
+		}
+	}
+	return NewCourseSearcherWithTitles(courses, titles)
+}
+
+// NewCourseSearcherWithTitles constructs a CourseSearcher of the courses, which can also be found by their titles.
+// e.g. {'CPSC 221': 'Basic Algorithms and Data Structures'}
+func NewCourseSearcherWithTitles(courses []string, titles map[string]string) CourseSearcher {
+	s := &DefaultCourseSearcher{}
+	for _, c := range courses {
+		entry := searchEntry{
+			code:       c,
+			normalized: normalizeCourse(c),
+			title:      words(titles[c]),
+		}
+		if i := strings.LastIndex(c, " "); i != -1 {
+			entry.number = normalizeCourse(c[i+1:])
+		}
+		s.courses = append(s.courses, entry)
+	}
+	return s
+}
+
+// Search returns the courses matching the query from the best to the worst match.
+func (s *DefaultCourseSearcher) Search(query string, limit int) []SearchResult {
+	normalized := normalizeCourse(query)
+	if normalized == "" {
+		return []SearchResult{}
+	}
+	queryWords := words(query)
+	maxDistance := maxTypos(normalized)
+
+	results := []SearchResult{}
+	for _, c := range s.courses {
+		result := SearchResult{Course: c.code}
+		switch {
+		case c.normalized == normalized:
+			result.Match = MatchExact
+		case strings.HasPrefix(c.normalized, normalized):
+			result.Match = MatchPrefix
+		case startsWithDigit(normalized) && strings.HasPrefix(c.number, normalized):
+			result.Match = MatchNumber
+		case len(c.title) != 0 && matchesWords(c.title, queryWords):
+			result.Match = MatchTitle
+		default:
+			code := c.normalized
+			if len(code) > len(normalized) {
+				// Compare with the start of the code so the query can be a typo of a prefix.
+				code = code[:len(normalized)]
+			}
+			distance := editDistance(normalized, code)
+			if distance == 0 || distance > maxDistance {
+				continue
+			}
+			result.Match, result.Distance = MatchFuzzy, distance
+		}
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if matchRanks[a.Match] != matchRanks[b.Match] {
+			return matchRanks[a.Match] < matchRanks[b.Match]
+		}
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if len(a.Course) != len(b.Course) {
+			return len(a.Course) < len(b.Course)
+		}
+		return a.Course < b.Course
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// normalizeCourse returns the letters and digits of a course code in upper case. e.g. 'cpsc-221' -> 'CPSC221'
+func normalizeCourse(code string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, code)
+}
+
+// words returns the words of the text in lower case.
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// matchesWords returns true if every query word is the start of a word of the title.
+func matchesWords(title, query []string) bool {
+	for _, q := range query {
+		found := false
+		for _, w := range title {
+			if strings.HasPrefix(w, q) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// maxTypos returns the number of typos tolerated in a normalized query, short queries have too many matches to allow any.
+func maxTypos(query string) int {
+	switch {
+	case len(query) < 4:
+		return 0
+	case len(query) < 7:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func startsWithDigit(s string) bool {
+	return s != "" && isDigit(s[0])
+}
+
+func isDigit(b byte) bool {
+	return '0' <= b && b <= '9'
+}
+
+// editDistance returns the number of insertions, deletions, substitutions and transpositions of
+// adjacent characters to turn a into b. e.g. 'CSPC' -> 'CPSC' is 1
+func editDistance(a, b string) int {
+	// d[i][j] is the distance between a[:i] and b[:j].
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
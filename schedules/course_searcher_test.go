@@ -0,0 +1,68 @@
+package schedules_test
+
+import (
+	"testing"
+
+	"github.com/smart-cs/scheduler-backend/database"
+	"github.com/smart-cs/scheduler-backend/schedules"
+	"github.com/stretchr/testify/assert"
+)
+
+func searchCourses(results []schedules.SearchResult) []string {
+	var courses []string
+	for _, r := range results {
+		courses = append(courses, r.Course)
+	}
+	return courses
+}
+
+func TestCourseSearcher(t *testing.T) {
+	database.LoadLocalDatabase("../database/test-coursedb.json")
+	assert := assert.New(t)
+	s, err := schedules.NewCourseSearcher()
+	assert.NoError(err)
+
+	for _, query := range []string{"CPSC 221", "cpsc221", "CPSC-221", " cpsc  221 "} {
+		results := s.Search(query, 0)
+		assert.Equal(schedules.SearchResult{Course: "CPSC 221", Match: schedules.MatchExact}, results[0], query)
+	}
+
+	t.Log("prefixes are ranked by length then lexicographically")
+	results := s.Search("cpsc 5", 3)
+	assert.Equal([]string{"CPSC 500", "CPSC 501", "CPSC 507"}, searchCourses(results))
+	assert.Equal(schedules.MatchPrefix, results[0].Match)
+	results = s.Search("CPSC", 0)
+	assert.Equal(schedules.MatchPrefix, results[73].Match)
+	assert.Equal(schedules.MatchFuzzy, results[74].Match, "typos of the department go after it")
+
+	t.Log("numbers match without the department")
+	results = s.Search("221", 0)
+	assert.Contains(searchCourses(results), "CPSC 221")
+	assert.Contains(searchCourses(results), "MATH 221")
+	for _, r := range results {
+		assert.Equal(schedules.MatchNumber, r.Match)
+	}
+	assert.Equal("LAW 221", results[0].Course, "shorter codes go first")
+
+	t.Log("typos are tolerated")
+	results = s.Search("CSPC 221", 0)
+	assert.Equal(schedules.SearchResult{Course: "CPSC 221", Match: schedules.MatchFuzzy, Distance: 1}, results[0])
+	assert.Contains(searchCourses(s.Search("CSPC", 0)), "CPSC 110")
+	assert.Empty(s.Search("XYZ", 0), "short queries aren't fuzzy")
+
+	assert.Empty(s.Search("", 0))
+	assert.Empty(s.Search("--", 0))
+}
+
+func TestCourseSearcher_Titles(t *testing.T) {
+	assert := assert.New(t)
+	s := schedules.NewCourseSearcherWithTitles([]string{"CPSC 221", "CPSC 110", "MATH 100"}, map[string]string{
+		"CPSC 221": "Basic Algorithms and Data Structures",
+		"CPSC 110": "Computation, Programs, and Programming",
+	})
+
+	assert.Equal([]schedules.SearchResult{{Course: "CPSC 221", Match: schedules.MatchTitle}}, s.Search("data struct", 0))
+	assert.Equal([]string{"CPSC 110"}, searchCourses(s.Search("Programming", 0)))
+	assert.Empty(s.Search("data programs", 0))
+	assert.Equal([]string{"CPSC 110", "CPSC 221"}, searchCourses(s.Search("cpsc", 0)))
+}
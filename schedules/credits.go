@@ -0,0 +1,97 @@
+package schedules
+
+import "github.com/smart-cs/scheduler-backend/models"
+
+// winterTerms are the terms credits are counted in.
+var winterTerms = []struct {
+	name string
+	set  models.TermSet
+}{
+	{"1", models.Term1},
+	{"2", models.Term2},
+}
+
+// termCredits are the credits of a schedule in term 1 and term 2.
+type termCredits [2]float64
+
+// plus returns the credits with the credits of a course in the terms added,
+// split evenly between both terms for a course in term 1 and 2.
+func (c termCredits) plus(credits float64, terms models.TermSet) termCredits {
+	if terms&models.Term1 != 0 && terms&models.Term2 != 0 {
+		credits /= 2
+	}
+	for i, t := range winterTerms {
+		if terms&t.set != 0 {
+			c[i] += credits
+		}
+	}
+	return c
+}
+
+// blockTerms returns the terms of a block of sections found in the term.
+func blockTerms(block []models.CourseSection, term string) models.TermSet {
+	var terms models.TermSet
+	for _, section := range block {
+		for _, session := range section.Sessions {
+			terms |= session.Terms()
+		}
+	}
+	if terms &= models.Term1 | models.Term2; terms == 0 {
+		// Sessions without a time or term, count the credits in the term the sections were found in.
+		return models.ParseTermSet(term)
+	}
+	return terms
+}
+
+// creditLimits are the bounds of the credits of a schedule in each of its terms.
+type creditLimits struct {
+	min, max float64
+	terms    models.TermSet
+}
+
+func newCreditLimits(options ScheduleSelectOptions) creditLimits {
+	return creditLimits{
+		min:   options.MinCredits,
+		max:   options.MaxCredits,
+		terms: models.ParseTermSet(options.Term) & (models.Term1 | models.Term2),
+	}
+}
+
+// set returns true if there is a minimum or a maximum.
+func (l creditLimits) set() bool {
+	return l.min > 0 || l.max > 0
+}
+
+// reached returns true if the credits are at least the minimum in every term.
+func (l creditLimits) reached(credits termCredits) bool {
+	for i, t := range winterTerms {
+		if l.terms&t.set != 0 && l.min > 0 && credits[i] < l.min {
+			return false
+		}
+	}
+	return true
+}
+
+// exceeded returns true if the credits are above the maximum in any term.
+func (l creditLimits) exceeded(credits termCredits) bool {
+	for i, t := range winterTerms {
+		if l.terms&t.set != 0 && l.max > 0 && credits[i] > l.max {
+			return true
+		}
+	}
+	return false
+}
+
+// byTerm returns the credits of every term of the limits. e.g. {'1': 7, '2': 4}
+func (l creditLimits) byTerm(credits termCredits) map[string]float64 {
+	if l.terms == 0 {
+		return nil
+	}
+	byTerm := make(map[string]float64)
+	for i, t := range winterTerms {
+		if l.terms&t.set != 0 {
+			byTerm[t.name] = credits[i]
+		}
+	}
+	return byTerm
+}
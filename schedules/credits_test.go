@@ -0,0 +1,134 @@
+package schedules_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/smart-cs/scheduler-backend/database"
+	"github.com/smart-cs/scheduler-backend/models"
+	"github.com/smart-cs/scheduler-backend/schedules"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupCreditsTests returns a ScheduleCreator of the test database with the credits of the test course credits.
+func setupCreditsTests(t *testing.T) schedules.ScheduleCreator {
+	setupScheduleCreatorTests()
+	var credits database.CourseCredits
+	assert.NoError(t, database.LoadJSONFile("../database/test-course-credits.json", &credits))
+	return creatorWithCredits(t, credits)
+}
+
+// creatorWithCredits returns a ScheduleCreator of the current database with the credits.
+func creatorWithCredits(t *testing.T, credits database.CourseCredits) schedules.ScheduleCreator {
+	catalog := courseCatalog(t).WithCourseCredits(credits)
+	return schedules.NewDatastoreScheduleCreator(database.NewCatalogDatastore(catalog))
+}
+
+func courseNames(schedule models.Schedule) []string {
+	var names []string
+	for _, section := range schedule.Courses {
+		name := section.Name[:len(section.Name)-4]
+		if len(names) == 0 || names[len(names)-1] != name {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func TestScheduleCreator_CreditLimits(t *testing.T) {
+	sc := setupCreditsTests(t)
+	assert := assert.New(t)
+
+	t.Log("choose 7 or 8 credits from a pool of 4, 4, 3 and 3 credit courses")
+	options := schedules.ScheduleSelectOptions{
+		Term:            "1",
+		OptionalCourses: []string{"CPSC 221", "CPSC 121", "MATH 220", "BIOL 111"},
+		MinCredits:      7,
+		MaxCredits:      8,
+	}
+	assert.Empty(sc.Validate(nil, options))
+	result := sc.Create(nil, options)
+	assert.NotEmpty(result)
+	subsets := make(map[string]bool)
+	for _, schedule := range result {
+		assert.True(schedule.Credits >= 7 && schedule.Credits <= 8, "%v", schedule.Credits)
+		assert.Equal(map[string]float64{"1": schedule.Credits}, schedule.TermCredits)
+		assert.Len(courseNames(schedule), 2)
+		subsets[fmt.Sprint(courseNames(schedule))] = true
+	}
+	assert.True(subsets["[CPSC 221 CPSC 121]"])
+	assert.True(subsets["[CPSC 221 MATH 220]"])
+	assert.False(subsets["[MATH 220 BIOL 111]"], "6 credits is too few")
+
+	t.Log("required courses are in every schedule")
+	options = schedules.ScheduleSelectOptions{
+		Term:            "1-2",
+		OptionalCourses: []string{"MATH 220", "BIOL 111"},
+		MaxCredits:      4,
+	}
+	result = sc.Create([]string{"CPSC 221"}, options)
+	assert.NotEmpty(result)
+	withOptional := 0
+	for i, schedule := range result {
+		assert.Equal("CPSC 221", courseNames(schedule)[0])
+		assert.True(schedule.TermCredits["1"] <= 4 && schedule.TermCredits["2"] <= 4, "%v", schedule.TermCredits)
+		assert.True(len(courseNames(schedule)) <= 2, "only one optional course fits in the other term")
+		assert.Len(schedule.Dropped, 3-len(courseNames(schedule)))
+		for _, dropped := range schedule.Dropped {
+			assert.NotContains(courseNames(schedule), dropped)
+		}
+		if len(courseNames(schedule)) > 1 {
+			withOptional++
+			assert.Equal(i, withOptional-1, "schedules with more courses come first")
+		}
+	}
+	assert.NotZero(withOptional)
+	assert.NotEqual(len(result), withOptional, "optional courses can be left out")
+
+	t.Log("every choice of optional courses within the credits is a schedule")
+	options = schedules.ScheduleSelectOptions{
+		Term:            "1",
+		OptionalCourses: []string{"CPSC 221", "CPSC 121", "MATH 220", "BIOL 111"},
+		MinCredits:      7,
+		MaxCredits:      14,
+	}
+	credits := make(map[float64]bool)
+	for _, schedule := range sc.Create(nil, options) {
+		credits[schedule.Credits] = true
+	}
+	assert.Equal(map[float64]bool{7: true, 8: true, 10: true, 11: true, 14: true}, credits)
+
+	t.Log("the credits of a course in term 1 and 2 are split between them")
+	sc = creatorWithCredits(t, database.CourseCredits{"APBI 499": 6})
+	result = sc.Create([]string{"APBI 499"}, schedules.ScheduleSelectOptions{Term: "1-2", MaxCredits: 3})
+	assert.Len(result, 1)
+	assert.Equal(6.0, result[0].Credits)
+	assert.Equal(map[string]float64{"1": 3, "2": 3}, result[0].TermCredits)
+
+	t.Log("there are no schedules if the credits can't be reached")
+	sc = setupCreditsTests(t)
+	options = schedules.ScheduleSelectOptions{Term: "1", OptionalCourses: []string{"MATH 220"}, MinCredits: 12}
+	assert.Empty(sc.Create([]string{"CPSC 221"}, options))
+	diagnosis := sc.Diagnose([]string{"CPSC 221"}, options)
+	assert.Equal("no schedule of the courses has at least 12 credits in each term", diagnosis.Message)
+}
+
+func TestScheduleCreator_ValidateCredits(t *testing.T) {
+	sc := setupCreditsTests(t)
+	assert := assert.New(t)
+
+	errs := sc.Validate(nil, schedules.ScheduleSelectOptions{Term: "1", OptionalCourses: []string{"CPSC 999"}, MinCredits: 15, MaxCredits: 12})
+	assert.Equal([]string{schedules.ErrUnknownCourse, schedules.ErrBadParameter}, validationCodes(errs))
+	assert.Equal("optional", errs[0].Param)
+	assert.Equal("min_credits", errs[1].Param)
+
+	errs = sc.Validate([]string{"CPSC 121"}, schedules.ScheduleSelectOptions{Term: "1", MaxCredits: -1})
+	assert.Equal([]string{schedules.ErrBadParameter}, validationCodes(errs))
+
+	errs = sc.Validate([]string{"CPSC 121"}, schedules.ScheduleSelectOptions{
+		Term:            "1",
+		OptionalCourses: []string{"CPSC 221"},
+		PinnedSections:  []string{"CPSC 221 101"},
+	})
+	assert.Empty(errs, "sections of optional courses can be pinned")
+}
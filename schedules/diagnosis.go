@@ -0,0 +1,268 @@
+package schedules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/smart-cs/scheduler-backend/models"
+)
+
+// Reasons of CourseDiagnosis.
+const (
+	// ReasonOK is the reason of a course which isn't why there are no schedules.
+	ReasonOK = "ok"
+	// ReasonNoSectionsInTerm is the reason of a course without sections in the requested term.
+	ReasonNoSectionsInTerm = "no_sections_in_term"
+	// ReasonNoCandidates is the reason of a course whose sections were all left out by the options,
+	// e.g. by statuses, excluded sections, blocked times or missing labs.
+	ReasonNoCandidates = "no_candidates"
+	// ReasonConflicts is the reason of a course which conflicts with the other courses.
+	ReasonConflicts = "conflicts"
+)
+
+// Constraints of Elimination, named after the parameters of /schedules.
+const (
+	ConstraintExcludeStatus = "exclude_status"
+	ConstraintPinned        = "pinned"
+	ConstraintExcluded      = "excluded"
+	ConstraintBlocked       = "blocked"
+)
+
+// Diagnosis explains why there are no schedules for some courses.
+type Diagnosis struct {
+	Courses []CourseDiagnosis `json:"courses"`
+	// Core is a minimal set of courses which can't be in a schedule together,
+	// every schedule is possible again if any one of them is dropped. Only set by Explain.
+	Core []CoreCourse `json:"core,omitempty"`
+	// Eliminations are the candidates left out by each constraint, from the most to the fewest. Only set by Explain.
+	Eliminations []Elimination `json:"eliminations,omitempty"`
+	// Message explains why there are no schedules when it isn't because of a course, e.g. the credit limits.
+	Message string `json:"message,omitempty"`
+}
+
+// CoreCourse is a course of an unsatisfiable core.
+type CoreCourse struct {
+	Course string `json:"course"`
+	// Sections are the lectures, seminars or studios of the course left after applying the options.
+	Sections []string `json:"sections"`
+}
+
+// Elimination is the number of candidates of a course left out by a constraint.
+type Elimination struct {
+	// Constraint is one of the Constraint constants. e.g. 'blocked'
+	Constraint string `json:"constraint"`
+	Course     string `json:"course"`
+	// Eliminated is the number of section combinations of the course the constraint left out on its own.
+	Eliminated int `json:"eliminated"`
+}
+
+// CourseDiagnosis explains why a course can't be in a schedule.
+type CourseDiagnosis struct {
+	Course string `json:"course"`
+	// Reason is one of the Reason constants. e.g. 'no_sections_in_term'
+	Reason string `json:"reason"`
+	// Sections is the number of lectures, seminars or studios of the course in the term.
+	Sections int `json:"sections"`
+	// Candidates is the number of section combinations of the course left after applying the options.
+	Candidates int    `json:"candidates"`
+	Message    string `json:"message"`
+}
+
+// Diagnose explains why there are no schedules for the courses with the options.
+// Courses which don't exist are skipped like they are when creating schedules.
+func (sc *DefaultScheduleCreator) Diagnose(courses []string, options ScheduleSelectOptions) Diagnosis {
+	options.Preferences = nil
+	candidates := sc.candidates(courses, options)
+	required := requiredCandidates(candidates)
+
+	allHaveCandidates := true
+	for _, c := range required {
+		if len(c.blocks) == 0 {
+			allHaveCandidates = false
+		}
+	}
+	conflicting := allHaveCandidates && len(required) > 1 && !sc.feasible(required)
+
+	diagnosis := Diagnosis{Courses: []CourseDiagnosis{}}
+	for _, c := range candidates {
+		d := CourseDiagnosis{
+			Course:     c.course,
+			Reason:     ReasonOK,
+			Sections:   sc.primarySections(c.course, options.Term),
+			Candidates: len(c.blocks),
+		}
+		switch {
+		case d.Sections == 0:
+			d.Reason = ReasonNoSectionsInTerm
+			d.Message = fmt.Sprintf("%s has no sections in term %s", c.course, options.Term)
+		case d.Candidates == 0:
+			d.Reason = ReasonNoCandidates
+			d.Message = fmt.Sprintf("none of the %d sections of %s in term %s are left after applying the options", d.Sections, c.course, options.Term)
+		case conflicting && !c.optional:
+			d.Reason = ReasonConflicts
+			d.Message = fmt.Sprintf("%s conflicts with the other courses", c.course)
+		}
+		diagnosis.Courses = append(diagnosis.Courses, d)
+	}
+	if allHaveCandidates && !conflicting && (options.MinCredits > 0 || options.MaxCredits > 0) {
+		diagnosis.Message = fmt.Sprintf("no schedule of the courses has %s in each term", creditRange(options))
+	}
+	return diagnosis
+}
+
+// creditRange describes the credit limits of the options. e.g. 'between 12 and 15 credits'
+func creditRange(options ScheduleSelectOptions) string {
+	switch {
+	case options.MaxCredits == 0:
+		return fmt.Sprintf("at least %g credits", options.MinCredits)
+	case options.MinCredits == 0:
+		return fmt.Sprintf("at most %g credits", options.MaxCredits)
+	default:
+		return fmt.Sprintf("between %g and %g credits", options.MinCredits, options.MaxCredits)
+	}
+}
+
+// requiredCandidates returns the candidates of the courses which can't be left out of a schedule.
+func requiredCandidates(candidates []courseCandidates) []courseCandidates {
+	var required []courseCandidates
+	for _, c := range candidates {
+		if !c.optional {
+			required = append(required, c)
+		}
+	}
+	return required
+}
+
+// feasible returns true if there is a schedule with a block of every course.
+func (sc *DefaultScheduleCreator) feasible(candidates []courseCandidates) bool {
+	if len(candidates) == 0 {
+		return true
+	}
+	courses := make([]courseCandidates, len(candidates))
+	for i, c := range candidates {
+		// Renumber the courses, they may be a subset of the requested courses.
+		c.position = i
+		courses[i] = c
+	}
+	found := false
+	search := newScheduleSearch(sc.helper, courses, func(models.Schedule) bool {
+		found = true
+		return false
+	})
+	search.run(0)
+	return found
+}
+
+// primarySections returns the number of primary sections of a course with a meeting in the term, without applying any options.
+func (sc *DefaultScheduleCreator) primarySections(c, term string) int {
+	return len(sc.ds.GetSections(c, term, models.PrimaryActivities...))
+}
+
+// Explain is Diagnose with an unsatisfiable core and the candidates left out by each constraint.
+// It searches for schedules once for every course so it's slower than Diagnose.
+func (sc *DefaultScheduleCreator) Explain(courses []string, options ScheduleSelectOptions) Diagnosis {
+	options.Preferences = nil
+	diagnosis := sc.Diagnose(courses, options)
+	diagnosis.Core = sc.unsatisfiableCore(requiredCandidates(sc.candidates(courses, options)))
+	diagnosis.Eliminations = sc.eliminations(courses, options)
+
+	inCore := make(map[string]bool)
+	var coreNames []string
+	for _, c := range diagnosis.Core {
+		inCore[c.Course] = true
+		coreNames = append(coreNames, c.Course)
+	}
+	for i, d := range diagnosis.Courses {
+		if d.Reason != ReasonConflicts {
+			continue
+		}
+		if !inCore[d.Course] {
+			diagnosis.Courses[i].Reason = ReasonOK
+			diagnosis.Courses[i].Message = ""
+			continue
+		}
+		diagnosis.Courses[i].Message = fmt.Sprintf("%s conflicts with %s", d.Course, strings.Join(without(coreNames, d.Course), ", "))
+	}
+	return diagnosis
+}
+
+// unsatisfiableCore returns a minimal set of the courses which can't be in a schedule together,
+// found by dropping every course which isn't needed to keep the set infeasible. Returns nil if it's feasible.
+func (sc *DefaultScheduleCreator) unsatisfiableCore(candidates []courseCandidates) []CoreCourse {
+	if sc.feasible(candidates) {
+		return nil
+	}
+	core := candidates
+	for i := 0; i < len(core); {
+		rest := append(append([]courseCandidates{}, core[:i]...), core[i+1:]...)
+		if !sc.feasible(rest) {
+			core = rest
+		} else {
+			i++
+		}
+	}
+
+	result := make([]CoreCourse, 0, len(core))
+	for _, c := range core {
+		course := CoreCourse{Course: c.course, Sections: []string{}}
+		for _, block := range c.blocks {
+			if !models.ContainsName(course.Sections, block[0].Name) {
+				course.Sections = append(course.Sections, block[0].Name)
+			}
+		}
+		result = append(result, course)
+	}
+	return result
+}
+
+// eliminations returns the candidates of every course left out by each constraint on its own, from the most to the fewest.
+func (sc *DefaultScheduleCreator) eliminations(courses []string, options ScheduleSelectOptions) []Elimination {
+	unconstrained := options
+	unconstrained.ExcludeStatuses = []models.SectionStatus{}
+	unconstrained.PinnedSections = nil
+	unconstrained.ExcludedSections = nil
+	unconstrained.BlockedTimes = nil
+
+	constrained := map[string]ScheduleSelectOptions{}
+	with := unconstrained
+	with.ExcludeStatuses = options.ExcludeStatuses
+	constrained[ConstraintExcludeStatus] = with
+	with = unconstrained
+	with.PinnedSections = options.PinnedSections
+	constrained[ConstraintPinned] = with
+	with = unconstrained
+	with.ExcludedSections = options.ExcludedSections
+	constrained[ConstraintExcluded] = with
+	with = unconstrained
+	with.BlockedTimes = options.BlockedTimes
+	constrained[ConstraintBlocked] = with
+
+	eliminations := []Elimination{}
+	for _, all := range sc.candidates(courses, unconstrained) {
+		for _, constraint := range []string{ConstraintExcludeStatus, ConstraintPinned, ConstraintExcluded, ConstraintBlocked} {
+			left := sc.candidates([]string{all.course}, constrained[constraint])[0]
+			if eliminated := len(all.blocks) - len(left.blocks); eliminated > 0 {
+				eliminations = append(eliminations, Elimination{
+					Constraint: constraint,
+					Course:     all.course,
+					Eliminated: eliminated,
+				})
+			}
+		}
+	}
+	sort.SliceStable(eliminations, func(i, j int) bool {
+		return eliminations[i].Eliminated > eliminations[j].Eliminated
+	})
+	return eliminations
+}
+
+func without(names []string, name string) []string {
+	var result []string
+	for _, n := range names {
+		if n != name {
+			result = append(result, n)
+		}
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+package schedules_test
+
+import (
+	"testing"
+
+	"github.com/smart-cs/scheduler-backend/models"
+	"github.com/smart-cs/scheduler-backend/schedules"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleCreator_Diagnose(t *testing.T) {
+	setupScheduleCreatorTests()
+	assert := assert.New(t)
+	sc := newScheduleCreator(t)
+	options := schedules.ScheduleSelectOptions{Term: "1-2"}
+
+	t.Log("APSC 210 only has work placements")
+	diagnosis := sc.Diagnose([]string{"CPSC 121", "APSC 210", "non-existent-course 101"}, options)
+	assert.Len(diagnosis.Courses, 2)
+	assert.Equal(schedules.CourseDiagnosis{
+		Course:     "CPSC 121",
+		Reason:     schedules.ReasonOK,
+		Sections:   6,
+		Candidates: 6,
+	}, diagnosis.Courses[0])
+	assert.Equal("APSC 210", diagnosis.Courses[1].Course)
+	assert.Equal(schedules.ReasonNoSectionsInTerm, diagnosis.Courses[1].Reason)
+	assert.Zero(diagnosis.Courses[1].Sections)
+	assert.NotEmpty(diagnosis.Courses[1].Message)
+
+	t.Log("ANTH 201A only has a cancelled lecture")
+	diagnosis = sc.Diagnose([]string{"ANTH 201A"}, options)
+	assert.Equal(schedules.ReasonNoCandidates, diagnosis.Courses[0].Reason)
+	assert.Equal(1, diagnosis.Courses[0].Sections)
+	assert.Zero(diagnosis.Courses[0].Candidates)
+
+	t.Log("PCTH 300 only has a year-long lecture, counted once in term 1-2 and in each term")
+	for _, term := range []string{"1-2", "1", "2"} {
+		diagnosis = sc.Diagnose([]string{"PCTH 300"}, schedules.ScheduleSelectOptions{Term: term, ExcludedSections: []string{"PCTH 300 001"}})
+		assert.Equal(schedules.ReasonNoCandidates, diagnosis.Courses[0].Reason, term)
+		assert.Equal(1, diagnosis.Courses[0].Sections, term)
+	}
+
+	t.Log("MATH 220 101 and BIOL 111 101 are both on Mon Wed Fri 12:00-13:00")
+	options = schedules.ScheduleSelectOptions{Term: "1", PinnedSections: []string{"MATH 220 101"}}
+	courses := []string{"MATH 220", "BIOL 111"}
+	assert.Empty(sc.Create(courses, options))
+	diagnosis = sc.Diagnose(courses, options)
+	for _, d := range diagnosis.Courses {
+		assert.Equal(schedules.ReasonConflicts, d.Reason, d.Course)
+		assert.Equal(1, d.Candidates, d.Course)
+	}
+}
+
+func TestScheduleCreator_Explain(t *testing.T) {
+	setupScheduleCreatorTests()
+	assert := assert.New(t)
+	sc := newScheduleCreator(t)
+
+	t.Log("MATH 220 101 and BIOL 111 101 conflict, CPSC 221 fits with either")
+	options := schedules.ScheduleSelectOptions{Term: "1", PinnedSections: []string{"MATH 220 101"}}
+	courses := []string{"CPSC 221", "MATH 220", "BIOL 111"}
+	diagnosis := sc.Explain(courses, options)
+	assert.Equal([]schedules.CoreCourse{
+		{Course: "MATH 220", Sections: []string{"MATH 220 101"}},
+		{Course: "BIOL 111", Sections: []string{"BIOL 111 101"}},
+	}, diagnosis.Core)
+	assert.Equal([]schedules.Elimination{
+		{Constraint: schedules.ConstraintPinned, Course: "MATH 220", Eliminated: 5},
+	}, diagnosis.Eliminations)
+	assert.Equal(schedules.ReasonOK, diagnosis.Courses[0].Reason)
+	assert.Equal(schedules.ReasonConflicts, diagnosis.Courses[1].Reason)
+	assert.Equal("MATH 220 conflicts with BIOL 111", diagnosis.Courses[1].Message)
+	assert.Equal(schedules.ReasonConflicts, diagnosis.Courses[2].Reason)
+
+	t.Log("a course without candidates is a core on its own")
+	diagnosis = sc.Explain([]string{"CPSC 221", "ANTH 201A"}, schedules.ScheduleSelectOptions{Term: "1-2"})
+	assert.Equal([]schedules.CoreCourse{{Course: "ANTH 201A", Sections: []string{}}}, diagnosis.Core)
+	assert.Equal([]schedules.Elimination{
+		{Constraint: schedules.ConstraintExcludeStatus, Course: "ANTH 201A", Eliminated: 1},
+	}, diagnosis.Eliminations)
+
+	t.Log("there's no core if there are schedules")
+	diagnosis = sc.Explain([]string{"CPSC 221", "CPSC 121"}, schedules.ScheduleSelectOptions{
+		Term:         "1-2",
+		BlockedTimes: []models.TimeBlock{{Term: "1-2", Day: "Tue", Start: 800, End: 1000}},
+	})
+	assert.Nil(diagnosis.Core)
+	assert.Equal([]schedules.Elimination{
+		{Constraint: schedules.ConstraintBlocked, Course: "CPSC 221", Eliminated: 1},
+	}, diagnosis.Eliminations)
+}
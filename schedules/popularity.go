@@ -0,0 +1,37 @@
+package schedules
+
+import "sync"
+
+// Popularity counts how often courses are requested, so the courses students look for the most can be ranked first.
+// It's safe to use from multiple goroutines.
+type Popularity struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// NewPopularity constructs a Popularity without any requests.
+func NewPopularity() *Popularity {
+	return &Popularity{counts: make(map[string]int)}
+}
+
+// Record counts a request of every course, ignored if p is nil.
+func (p *Popularity) Record(courses ...string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, c := range courses {
+		p.counts[c]++
+	}
+}
+
+// Count returns the number of requests of a course, 0 if p is nil.
+func (p *Popularity) Count(course string) int {
+	if p == nil {
+		return 0
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.counts[course]
+}
@@ -0,0 +1,32 @@
+package schedules_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/smart-cs/scheduler-backend/schedules"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPopularity(t *testing.T) {
+	assert := assert.New(t)
+	p := schedules.NewPopularity()
+	assert.Zero(p.Count("CPSC 121"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Record("CPSC 121", "CPSC 221")
+		}()
+	}
+	wg.Wait()
+	p.Record("CPSC 121")
+	assert.Equal(11, p.Count("CPSC 121"))
+	assert.Equal(10, p.Count("CPSC 221"))
+
+	var none *schedules.Popularity
+	none.Record("CPSC 121")
+	assert.Zero(none.Count("CPSC 121"))
+}
@@ -0,0 +1,272 @@
+package schedules
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/smart-cs/scheduler-backend/models"
+)
+
+// Preference scores how well a schedule matches what a student prefers,
+// from 0 (doesn't match at all) to 1 (perfect match).
+type Preference interface {
+	Score(schedule models.Schedule) float64
+}
+
+// PreferenceFunc is an adapter to use ordinary functions as a Preference.
+type PreferenceFunc func(schedule models.Schedule) float64
+
+// Score calls f(schedule).
+func (f PreferenceFunc) Score(schedule models.Schedule) float64 {
+	return f(schedule)
+}
+
+// WeightedPreference is a Preference with how much it matters compared to other preferences.
+type WeightedPreference struct {
+	Preference Preference
+	Weight     float64
+}
+
+// Score returns the weighted sum of the preference scores of a schedule.
+// The score is always finite: weighted scores which aren't a number are left out,
+// and a sum past the range of float64 is the largest or smallest float64.
+func Score(schedule models.Schedule, preferences []WeightedPreference) float64 {
+	score := 0.0
+	for _, p := range preferences {
+		if weighted := p.Weight * p.Preference.Score(schedule); !math.IsNaN(weighted) {
+			score += weighted
+		}
+	}
+	if math.IsNaN(score) {
+		// Only +Inf and -Inf add up to NaN.
+		return 0
+	}
+	return math.Max(-math.MaxFloat64, math.Min(score, math.MaxFloat64))
+}
+
+// Rank sorts schedules from the highest to the lowest score and sets their Score.
+// Schedules with the same score keep their order.
+func Rank(schedules []models.Schedule, preferences []WeightedPreference) {
+	for i := range schedules {
+		schedules[i].Score = Score(schedules[i], preferences)
+	}
+	sort.SliceStable(schedules, func(i, j int) bool {
+		return schedules[i].Score > schedules[j].Score
+	})
+}
+
+// rankedSchedule is a scored schedule with the order it was found in.
+type rankedSchedule struct {
+	schedule models.Schedule
+	found    int
+}
+
+// worse returns true if a is ranked after b: it has a lower score, or the same score and was found later.
+func worse(a, b rankedSchedule) bool {
+	if a.schedule.Score != b.schedule.Score {
+		return a.schedule.Score < b.schedule.Score
+	}
+	return a.found > b.found
+}
+
+// rankedHeap is a heap of the best schedules found so far, with the worst of them on top.
+type rankedHeap []rankedSchedule
+
+func (h rankedHeap) Len() int           { return len(h) }
+func (h rankedHeap) Less(i, j int) bool { return worse(h[i], h[j]) }
+func (h rankedHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *rankedHeap) Push(x interface{}) {
+	*h = append(*h, x.(rankedSchedule))
+}
+
+func (h *rankedHeap) Pop() interface{} {
+	last := (*h)[len(*h)-1]
+	*h = (*h)[:len(*h)-1]
+	return last
+}
+
+// sorted returns the schedules from the best to the worst.
+func (h rankedHeap) sorted() []models.Schedule {
+	sort.Slice(h, func(i, j int) bool { return worse(h[j], h[i]) })
+	schedules := make([]models.Schedule, len(h))
+	for i, ranked := range h {
+		schedules[i] = ranked.schedule
+	}
+	return schedules
+}
+
+// NoClassesBefore prefers schedules where classes start at or after a time (24 hour representation). e.g. 1000
+func NoClassesBefore(hhmm int) Preference {
+	return PreferenceFunc(func(schedule models.Schedule) float64 {
+		return fractionOfSessions(schedule, func(s models.ClassSession) bool {
+			return s.Start >= hhmm
+		})
+	})
+}
+
+// PreferAfternoons prefers schedules with classes starting at or after noon.
+func PreferAfternoons() Preference {
+	return PreferenceFunc(func(schedule models.Schedule) float64 {
+		return fractionOfSessions(schedule, func(s models.ClassSession) bool {
+			return s.Start >= 1200
+		})
+	})
+}
+
+// DaysOff prefers schedules without classes on the given days.
+func DaysOff(days models.Weekdays) Preference {
+	return PreferenceFunc(func(schedule models.Schedule) float64 {
+		var busy models.Weekdays
+		for _, s := range sessionsOf(schedule) {
+			busy |= s.Weekdays()
+		}
+		wanted := len(days.Days())
+		if wanted == 0 {
+			return 1
+		}
+		return float64(wanted-len((busy&days).Days())) / float64(wanted)
+	})
+}
+
+// CompactDays prefers schedules with classes on fewer days of the week.
+func CompactDays() Preference {
+	return PreferenceFunc(func(schedule models.Schedule) float64 {
+		days := dailySessions(schedule)
+		terms := make(map[models.TermSet]int)
+		for td := range days {
+			terms[td.term]++
+		}
+		if len(terms) == 0 {
+			return 1
+		}
+		// Every term contributes 1 with a single day of classes and 0 with classes every day.
+		score := 0.0
+		for _, n := range terms {
+			score += float64(7-n) / 6
+		}
+		return score / float64(len(terms))
+	})
+}
+
+// MinimizeGaps prefers schedules with less time between classes on the same day.
+func MinimizeGaps() Preference {
+	return PreferenceFunc(func(schedule models.Schedule) float64 {
+		gap := 0
+		for _, ranges := range dailySessions(schedule) {
+			sort.Slice(ranges, func(i, j int) bool { return ranges[i].start < ranges[j].start })
+			end := ranges[0].end
+			for _, r := range ranges[1:] {
+				if r.start > end {
+					gap += r.start - end
+				}
+				if r.end > end {
+					end = r.end
+				}
+			}
+		}
+		// An hour of gaps halves the score.
+		return 1 / (1 + float64(gap)/60)
+	})
+}
+
+// PreferenceNames are the names of the preferences which ParsePreference knows.
+var PreferenceNames = []string{"no_classes_before", "days_off", "minimize_gaps", "compact_days", "prefer_afternoons"}
+
+// ParsePreference returns the preference with the given name and argument.
+// e.g. ("no_classes_before", "10:00"), ("days_off", "Fri"), ("minimize_gaps", "true")
+// Returns a nil Preference if the argument turns the preference off. e.g. ("minimize_gaps", "false")
+func ParsePreference(name, arg string) (Preference, error) {
+	switch name {
+	case "no_classes_before":
+		hhmm, err := models.ParseTime(arg)
+		if err != nil {
+			return nil, err
+		}
+		return NoClassesBefore(hhmm), nil
+	case "days_off":
+		var days models.Weekdays
+		for _, day := range strings.FieldsFunc(arg, func(r rune) bool { return r == ',' || r == ' ' }) {
+			d := models.ParseWeekdays(day)
+			if d == 0 {
+				return nil, fmt.Errorf("invalid day %q, expected one of Mon, Tue, Wed, Thu, Fri, Sat, Sun", day)
+			}
+			days |= d
+		}
+		if days == 0 {
+			return nil, fmt.Errorf("no days given")
+		}
+		return DaysOff(days), nil
+	case "minimize_gaps", "compact_days", "prefer_afternoons":
+		on, err := strconv.ParseBool(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q, expected true or false", arg)
+		}
+		if !on {
+			return nil, nil
+		}
+		switch name {
+		case "minimize_gaps":
+			return MinimizeGaps(), nil
+		case "compact_days":
+			return CompactDays(), nil
+		}
+		return PreferAfternoons(), nil
+	}
+	return nil, fmt.Errorf("unknown preference %q", name)
+}
+
+func sessionsOf(schedule models.Schedule) []models.ClassSession {
+	var sessions []models.ClassSession
+	for _, c := range schedule.Courses {
+		sessions = append(sessions, c.Sessions...)
+	}
+	return sessions
+}
+
+// fractionOfSessions returns the fraction of sessions which satisfy ok, 1 if there are no sessions.
+func fractionOfSessions(schedule models.Schedule, ok func(models.ClassSession) bool) float64 {
+	sessions := sessionsOf(schedule)
+	if len(sessions) == 0 {
+		return 1
+	}
+	n := 0
+	for _, s := range sessions {
+		if ok(s) {
+			n++
+		}
+	}
+	return float64(n) / float64(len(sessions))
+}
+
+type termDay struct {
+	term models.TermSet
+	day  models.Weekdays
+}
+
+type minuteRange struct {
+	start, end int
+}
+
+// dailySessions groups the minute ranges of the sessions by the term and day they're on.
+func dailySessions(schedule models.Schedule) map[termDay][]minuteRange {
+	days := make(map[termDay][]minuteRange)
+	for _, s := range sessionsOf(schedule) {
+		start, end := s.MinuteRange()
+		for term := models.Term1; term <= models.TermD; term <<= 1 {
+			if s.Terms()&term == 0 {
+				continue
+			}
+			for day := models.Monday; day <= models.Sunday; day <<= 1 {
+				if s.Weekdays()&day != 0 {
+					td := termDay{term, day}
+					days[td] = append(days[td], minuteRange{start, end})
+				}
+			}
+		}
+	}
+	return days
+}
@@ -0,0 +1,160 @@
+package schedules_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/smart-cs/scheduler-backend/models"
+	"github.com/smart-cs/scheduler-backend/schedules"
+	"github.com/stretchr/testify/assert"
+)
+
+func scheduleWithSessions(sessions ...models.ClassSession) models.Schedule {
+	var courses []models.CourseSection
+	for _, s := range sessions {
+		courses = append(courses, models.CourseSection{
+			Name:     "COURSE " + s.Day + " " + s.Term,
+			Sessions: []models.ClassSession{s},
+		})
+	}
+	return models.Schedule{Courses: courses}
+}
+
+func session(term, day string, start, end int) models.ClassSession {
+	return models.ClassSession{Activity: "Lecture", Term: term, Day: day, Start: start, End: end}
+}
+
+func TestNoClassesBefore(t *testing.T) {
+	assert := assert.New(t)
+	p := schedules.NoClassesBefore(1000)
+
+	assert.Equal(1.0, p.Score(models.Schedule{}))
+	assert.Equal(1.0, p.Score(scheduleWithSessions(session("1", "Mon", 1000, 1100))))
+	assert.Equal(0.5, p.Score(scheduleWithSessions(
+		session("1", "Mon", 800, 900),
+		session("1", "Tue", 1100, 1200),
+	)))
+	assert.Equal(0.0, p.Score(scheduleWithSessions(session("1", "Mon", 930, 1100))))
+}
+
+func TestPreferAfternoons(t *testing.T) {
+	assert := assert.New(t)
+	p := schedules.PreferAfternoons()
+
+	assert.Equal(1.0, p.Score(scheduleWithSessions(session("1", "Mon", 1300, 1400))))
+	assert.Equal(0.0, p.Score(scheduleWithSessions(session("1", "Mon", 1100, 1300))))
+}
+
+func TestDaysOff(t *testing.T) {
+	assert := assert.New(t)
+	p := schedules.DaysOff(models.Friday | models.Monday)
+
+	assert.Equal(1.0, p.Score(scheduleWithSessions(session("1", "Tue", 900, 1000))))
+	assert.Equal(0.5, p.Score(scheduleWithSessions(session("1", "Fri", 900, 1000))))
+	assert.Equal(0.0, p.Score(scheduleWithSessions(
+		session("1", "Fri", 900, 1000),
+		session("2", "Mon", 900, 1000),
+	)))
+}
+
+func TestCompactDays(t *testing.T) {
+	assert := assert.New(t)
+	p := schedules.CompactDays()
+
+	oneDay := p.Score(scheduleWithSessions(session("1", "Mon", 900, 1000), session("1", "Mon", 1100, 1200)))
+	twoDays := p.Score(scheduleWithSessions(session("1", "Mon", 900, 1000), session("1", "Tue", 1100, 1200)))
+	assert.Equal(1.0, oneDay)
+	assert.True(oneDay > twoDays)
+	assert.InDelta(0.0, p.Score(scheduleWithSessions(session("1", "Mon Tue Wed Thu Fri Sat Sun", 900, 1000))), 1e-9)
+}
+
+func TestMinimizeGaps(t *testing.T) {
+	assert := assert.New(t)
+	p := schedules.MinimizeGaps()
+
+	assert.Equal(1.0, p.Score(scheduleWithSessions(
+		session("1", "Mon", 900, 1000),
+		session("1", "Mon", 1000, 1100),
+		session("2", "Mon", 1300, 1400),
+	)))
+	assert.Equal(0.5, p.Score(scheduleWithSessions(
+		session("1", "Mon", 900, 1000),
+		session("1", "Mon", 1100, 1200),
+	)))
+	assert.Equal(0.5, p.Score(scheduleWithSessions(
+		session("1", "Mon", 900, 1200),
+		session("1", "Mon", 1000, 1100),
+		session("1", "Mon", 1300, 1400),
+	)), "overlapping sessions shouldn't count as a gap")
+}
+
+func TestRank(t *testing.T) {
+	assert := assert.New(t)
+	early := scheduleWithSessions(session("1", "Mon", 800, 900))
+	late := scheduleWithSessions(session("1", "Mon", 1300, 1400))
+	lateFriday := scheduleWithSessions(session("1", "Fri", 1300, 1400))
+
+	ranked := []models.Schedule{early, lateFriday, late}
+	schedules.Rank(ranked, []schedules.WeightedPreference{
+		{Preference: schedules.NoClassesBefore(1000), Weight: 1},
+		{Preference: schedules.DaysOff(models.Friday), Weight: 2},
+	})
+	assert.Equal([]string{"COURSE Mon 1", "COURSE Mon 1", "COURSE Fri 1"}, []string{
+		ranked[0].Courses[0].Name, ranked[1].Courses[0].Name, ranked[2].Courses[0].Name,
+	})
+	assert.Equal(3.0, ranked[0].Score)
+	assert.Equal(1300, ranked[0].Courses[0].Sessions[0].Start)
+	assert.Equal(2.0, ranked[1].Score)
+	assert.Equal(1.0, ranked[2].Score)
+}
+
+func TestScore_Finite(t *testing.T) {
+	assert := assert.New(t)
+	late := scheduleWithSessions(session("1", "Mon", 1300, 1400))
+
+	assert.Equal(math.MaxFloat64, schedules.Score(late, []schedules.WeightedPreference{
+		{Preference: schedules.NoClassesBefore(1000), Weight: math.MaxFloat64},
+		{Preference: schedules.PreferAfternoons(), Weight: math.MaxFloat64},
+	}))
+	assert.Equal(0.0, schedules.Score(late, []schedules.WeightedPreference{
+		{Preference: schedules.NoClassesBefore(1000), Weight: math.Inf(1)},
+		{Preference: schedules.PreferAfternoons(), Weight: math.Inf(-1)},
+	}))
+	assert.Equal(1.0, schedules.Score(late, []schedules.WeightedPreference{
+		{Preference: schedules.NoClassesBefore(1000), Weight: 1},
+		{Preference: schedules.DaysOff(models.Monday), Weight: math.Inf(1)},
+	}))
+}
+
+func TestParsePreference(t *testing.T) {
+	assert := assert.New(t)
+	friday := scheduleWithSessions(session("1", "Fri", 900, 1000))
+
+	p, err := schedules.ParsePreference("no_classes_before", "10:00")
+	assert.NoError(err)
+	assert.Equal(0.0, p.Score(friday))
+
+	p, err = schedules.ParsePreference("days_off", "Mon,Fri")
+	assert.NoError(err)
+	assert.Equal(0.5, p.Score(friday))
+
+	for _, name := range []string{"minimize_gaps", "compact_days", "prefer_afternoons"} {
+		p, err = schedules.ParsePreference(name, "true")
+		assert.NoError(err)
+		assert.NotNil(p)
+		p, err = schedules.ParsePreference(name, "false")
+		assert.NoError(err)
+		assert.Nil(p)
+	}
+
+	_, err = schedules.ParsePreference("no_classes_before", "early")
+	assert.Error(err)
+	_, err = schedules.ParsePreference("days_off", "Fri,Caturday")
+	assert.Error(err)
+	_, err = schedules.ParsePreference("days_off", "")
+	assert.Error(err)
+	_, err = schedules.ParsePreference("minimize_gaps", "please")
+	assert.Error(err)
+	_, err = schedules.ParsePreference("bogus", "true")
+	assert.Error(err)
+}
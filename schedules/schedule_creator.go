@@ -1,13 +1,34 @@
 package schedules
 
 import (
+	"container/heap"
+	"sort"
+	"strings"
+
 	"github.com/smart-cs/scheduler-backend/database"
 	"github.com/smart-cs/scheduler-backend/models"
 )
 
 // ScheduleCreator is the interface to create schedules.
 type ScheduleCreator interface {
+	// Create returns all non-conflicting schedules given a list of courses.
 	Create(courses []string, options ScheduleSelectOptions) []models.Schedule
+
+	// ForEach calls fn with every non-conflicting schedule given a list of courses, until fn returns false.
+	ForEach(courses []string, options ScheduleSelectOptions, fn func(models.Schedule) bool)
+
+	// Ranked returns the n best schedules by the preferences of the options, or all of them if n is 0,
+	// with the number of schedules there are.
+	Ranked(courses []string, options ScheduleSelectOptions, n int) ([]models.Schedule, int)
+
+	// Validate returns the errors which prevent schedules from being created for the courses with the options.
+	Validate(courses []string, options ScheduleSelectOptions) []ValidationError
+
+	// Diagnose explains why there are no schedules for the courses with the options.
+	Diagnose(courses []string, options ScheduleSelectOptions) Diagnosis
+
+	// Explain is Diagnose with an unsatisfiable core and the candidates left out by each constraint.
+	Explain(courses []string, options ScheduleSelectOptions) Diagnosis
 }
 
 // DefaultScheduleCreator implements ScheduleCreator.
@@ -21,12 +42,51 @@ type ScheduleSelectOptions struct {
 	// Term must be 1, 2, 1-2
 	Term                   string
 	SelectLabsAndTutorials bool
+	// Preferences rank the schedules, the best schedules are returned first.
+	Preferences []WeightedPreference
+	// BlockedTimes are times where no class can be scheduled.
+	BlockedTimes []models.TimeBlock
+	// ExcludeStatuses are the statuses of sections which can't be in a schedule.
+	// Defaults to DefaultExcludeStatuses if nil.
+	ExcludeStatuses []models.SectionStatus
+	// PinnedSections are names of sections which must be in every schedule. e.g. 'CPSC 221 101'
+	PinnedSections []string
+	// ExcludedSections are names of sections which can't be in a schedule.
+	ExcludedSections []string
+	// OptionalCourses are courses which can be left out of a schedule, e.g. a pool of electives to choose from.
+	// Schedules have every requested course along with as many of the optional courses as fit together,
+	// or with credit limits, every choice of the optional courses within the limits with the most courses first.
+	// The optional courses left out are the Dropped courses of a schedule.
+	OptionalCourses []string
+	// MinCredits is the least credits a schedule can have in each of its terms, no minimum if 0.
+	MinCredits float64
+	// MaxCredits is the most credits a schedule can have in each of its terms, no maximum if 0.
+	MaxCredits float64
+}
+
+// DefaultExcludeStatuses are the statuses of sections excluded from schedules by default.
+var DefaultExcludeStatuses = []models.SectionStatus{models.Cancelled}
+
+func (o ScheduleSelectOptions) excludeStatuses() []models.SectionStatus {
+	if o.ExcludeStatuses == nil {
+		return DefaultExcludeStatuses
+	}
+	return o.ExcludeStatuses
+}
+
+// NewScheduleCreator constructs a new ScheduleCreator of the current database.
+func NewScheduleCreator() (ScheduleCreator, error) {
+	ds, err := database.NewDatastore()
+	if err != nil {
+		return nil, err
+	}
+	return NewDatastoreScheduleCreator(ds), nil
 }
 
-// NewScheduleCreator constructs a new ScheduleCreator.
-func NewScheduleCreator() ScheduleCreator {
+// NewDatastoreScheduleCreator constructs a new ScheduleCreator reading from the given Datastore.
+func NewDatastoreScheduleCreator(ds database.Datastore) ScheduleCreator {
 	return &DefaultScheduleCreator{
-		ds:     database.NewDatastore(),
+		ds:     ds,
 		helper: models.CourseHelper{},
 	}
 }
@@ -34,116 +94,367 @@ func NewScheduleCreator() ScheduleCreator {
 // Create returns all non-conflicting schedules given a list of courses.
 func (sc *DefaultScheduleCreator) Create(courses []string, options ScheduleSelectOptions) []models.Schedule {
 	var schedules []models.Schedule
-	for _, c := range courses {
-		// Skip invalid courses.
-		if !sc.ds.CourseExists(c) {
-			continue
+	sc.ForEach(courses, options, func(schedule models.Schedule) bool {
+		schedules = append(schedules, schedule)
+		return true
+	})
+	return schedules
+}
+
+// ForEach calls fn with every non-conflicting schedule given a list of courses, until fn returns false.
+// Schedules are found with a depth-first search so they are never all held in memory,
+// unless there are preferences to rank them by, use Ranked to hold only the best ones.
+func (sc *DefaultScheduleCreator) ForEach(courses []string, options ScheduleSelectOptions, fn func(models.Schedule) bool) {
+	if len(options.Preferences) != 0 {
+		sc.forEachRanked(courses, options, fn)
+		return
+	}
+
+	candidates := sc.candidates(courses, options)
+	if len(candidates) == 0 {
+		return
+	}
+	for _, c := range candidates {
+		if len(c.blocks) == 0 && !c.optional {
+			return
 		}
+	}
 
-		if options.Term == "1-2" {
-			newSchedulesTerm1, addedTerm1 := sc.addCourseToSchedules(schedules, c, "1", options.SelectLabsAndTutorials)
-			newSchedulesTerm2, addedTerm2 := sc.addCourseToSchedules(schedules, c, "2", options.SelectLabsAndTutorials)
-			if !addedTerm1 && !addedTerm2 {
-				return []models.Schedule{}
+	optional := 0
+	for _, c := range candidates {
+		if c.optional {
+			optional++
+		}
+	}
+	search := newScheduleSearch(sc.helper, candidates, fn)
+	search.limits = newCreditLimits(options)
+	// The best schedules have the most optional courses. Without credit limits, schedules with fewer are only searched
+	// if there are none, with limits every choice of optional courses within them is a schedule.
+	for search.include = optional; search.include >= 0; search.include-- {
+		if !search.run(0) || (search.found && !search.limits.set()) {
+			return
+		}
+	}
+}
+
+// candidates returns the section blocks which can be chosen for every existing course, in the order of the courses
+// followed by the optional courses.
+func (sc *DefaultScheduleCreator) candidates(courses []string, options ScheduleSelectOptions) []courseCandidates {
+	var blockedTimes []models.CourseSection
+	for _, b := range options.BlockedTimes {
+		blockedTimes = append(blockedTimes, b.Section())
+	}
+
+	var candidates []courseCandidates
+	add := func(c string, optional bool) {
+		// Skip invalid courses.
+		info, ok := sc.ds.GetCourse(c)
+		if !ok {
+			return
+		}
+		candidate := courseCandidates{
+			course:   c,
+			credits:  info.Credits,
+			optional: optional,
+			position: len(candidates),
+		}
+		found := make(map[string]bool)
+		for _, term := range queryTerms(options.Term) {
+			for _, block := range sc.withoutConflicts(sc.sectionBlocksInTerm(c, term, options), blockedTimes) {
+				// Blocks of term 1-2 sections are found in both terms.
+				key := blockKey(block)
+				if found[key] {
+					continue
+				}
+				found[key] = true
+				candidate.blocks = append(candidate.blocks, block)
+				candidate.terms = append(candidate.terms, blockTerms(block, term))
 			}
+		}
+		candidates = append(candidates, candidate)
+	}
+	for _, c := range courses {
+		add(c, false)
+	}
+	for _, c := range options.OptionalCourses {
+		if !models.ContainsName(courses, c) {
+			add(c, true)
+		}
+	}
+	return candidates
+}
 
-			schedules = append(newSchedulesTerm1, newSchedulesTerm2...)
-			continue
+// forEachRanked calls fn with every non-conflicting schedule from the best to the worst, until fn returns false.
+func (sc *DefaultScheduleCreator) forEachRanked(courses []string, options ScheduleSelectOptions, fn func(models.Schedule) bool) {
+	schedules, _ := sc.Ranked(courses, options, 0)
+	for _, schedule := range schedules {
+		if !fn(schedule) {
+			return
 		}
+	}
+}
 
-		newSchedules, added := sc.addCourseToSchedules(schedules, c, options.Term, options.SelectLabsAndTutorials)
-		if !added {
-			return []models.Schedule{}
+// Ranked returns the n best schedules by the preferences of the options, or all of them if n is 0,
+// with the number of schedules there are. Schedules with the same score keep the order they were found in.
+// Every schedule is scored as it's found, but only the n best are held in memory.
+func (sc *DefaultScheduleCreator) Ranked(courses []string, options ScheduleSelectOptions, n int) ([]models.Schedule, int) {
+	preferences := options.Preferences
+	options.Preferences = nil
+	var best rankedHeap
+	total := 0
+	sc.ForEach(courses, options, func(schedule models.Schedule) bool {
+		schedule.Score = Score(schedule, preferences)
+		ranked := rankedSchedule{schedule: schedule, found: total}
+		total++
+		if n == 0 || len(best) < n {
+			heap.Push(&best, ranked)
+		} else if worse(best[0], ranked) {
+			best[0] = ranked
+			heap.Fix(&best, 0)
 		}
-		schedules = newSchedules
+		return true
+	})
+	return best.sorted(), total
+}
+
+// queryTerms returns the terms to find sections in for a term of the options. e.g. '1-2' -> ['1', '2']
+func queryTerms(term string) []string {
+	if term == "1-2" {
+		return []string{"1", "2"}
 	}
-	return schedules
+	return []string{term}
 }
 
-func (sc *DefaultScheduleCreator) addCourseToSchedules(schedules []models.Schedule, c, term string, selectLabsAndTuts bool) ([]models.Schedule, bool) {
-	lectureSections := sc.ds.GetSections(c, term, models.Lecture, models.Seminar, models.Studio)
-	hasLabs := sc.ds.CourseHasSectionWithActivity(c, models.Laboratory)
-	hasTuts := sc.ds.CourseHasSectionWithActivity(c, models.Tutorial)
+// blockKey returns a key of the sections of a block.
+func blockKey(block []models.CourseSection) string {
+	names := make([]string, len(block))
+	for i, section := range block {
+		names[i] = section.Name
+	}
+	return strings.Join(names, ",")
+}
 
-	if !selectLabsAndTuts || (!hasLabs && !hasTuts) {
-		// Just add the lecture sections.
-		schedules = sc.addSections(schedules, lectureSections)
-		return schedules, len(schedules) != 0
+// sectionBlocksInTerm returns every non-conflicting combination of sections which completes the course in the term.
+func (sc *DefaultScheduleCreator) sectionBlocksInTerm(c, term string, options ScheduleSelectOptions) [][]models.CourseSection {
+	sections := func(activities ...models.ActivityType) []models.CourseSection {
+		return sc.ds.FindSections(database.SectionQuery{
+			Course:          c,
+			Term:            term,
+			Activities:      activities,
+			ExcludeStatuses: options.excludeStatuses(),
+			Pinned:          options.PinnedSections,
+			Excluded:        options.ExcludedSections,
+		})
 	}
 
-	// Add sections including Labs and Tutorials.
 	var sectionsArray [][]models.CourseSection
-	for _, section := range lectureSections {
+	for _, section := range sections(models.PrimaryActivities...) {
 		sectionsArray = append(sectionsArray, []models.CourseSection{section})
 	}
-	if hasLabs {
-		labSections := sc.ds.GetSections(c, term, models.Laboratory)
-		sectionsArray = sc.helper.CombinationsNoConflict(sectionsArray, labSections)
+	if !options.SelectLabsAndTutorials {
+		// Just the lecture sections.
+		return sectionsArray
 	}
-	if hasTuts {
-		tutSections := sc.ds.GetSections(c, term, models.Tutorial)
-		sectionsArray = sc.helper.CombinationsNoConflict(sectionsArray, tutSections)
+
+	// Add a section of every companion activity the course has, e.g. Labs and Tutorials.
+	for _, activity := range models.CompanionActivities {
+		if sc.ds.CourseHasSectionWithActivity(c, activity) {
+			sectionsArray = sc.addLinkedSections(sectionsArray, sections(activity))
+		}
 	}
-	schedules = sc.addSectionBlocks(schedules, sectionsArray)
-	return schedules, len(schedules) != 0
+	return sectionsArray
 }
 
-func (sc *DefaultScheduleCreator) addSectionBlocks(schedules []models.Schedule, sectionsArray [][]models.CourseSection) []models.Schedule {
-	if len(schedules) == 0 {
-		for _, sections := range sectionsArray {
-			schedules = append(schedules, models.Schedule{Courses: sections})
+// addLinkedSections adds every companion section linked to the primary section of a block to the block.
+func (sc *DefaultScheduleCreator) addLinkedSections(blocks [][]models.CourseSection, companions []models.CourseSection) [][]models.CourseSection {
+	var result [][]models.CourseSection
+	for _, block := range blocks {
+		var linked []models.CourseSection
+		for _, companion := range companions {
+			if sc.ds.SectionsLinked(block[0].Name, companion.Name) {
+				linked = append(linked, companion)
+			}
 		}
-		return schedules
+		result = append(result, sc.helper.CombinationsNoConflict([][]models.CourseSection{block}, linked)...)
 	}
+	return result
+}
 
-	newSchedules := []models.Schedule{}
-	addedASection := false
-	for _, schedule := range schedules {
-		for _, sections := range sectionsArray {
-			newSchedule, added := sc.addSection(schedule, sections...)
-			if added {
-				newSchedules = append(newSchedules, newSchedule)
-				addedASection = true
+// withoutConflicts returns the blocks which don't conflict with any of the fixed sections.
+func (sc *DefaultScheduleCreator) withoutConflicts(blocks [][]models.CourseSection, fixed []models.CourseSection) [][]models.CourseSection {
+	if len(fixed) == 0 {
+		return blocks
+	}
+	var kept [][]models.CourseSection
+	for _, block := range blocks {
+		if !sc.conflictsWithAny(block, fixed) {
+			kept = append(kept, block)
+		}
+	}
+	return kept
+}
+
+func (sc *DefaultScheduleCreator) conflictsWithAny(block, fixed []models.CourseSection) bool {
+	for _, section := range block {
+		for _, f := range fixed {
+			if sc.helper.SectionsConflict(section, f) {
+				return true
 			}
 		}
 	}
-	if !addedASection {
-		return []models.Schedule{}
+	return false
+}
+
+// courseCandidates holds the section blocks which can be chosen for a course.
+type courseCandidates struct {
+	course  string
+	credits float64
+	// optional courses can be left out of a schedule.
+	optional bool
+	// position of the course in the requested courses.
+	position int
+	blocks   [][]models.CourseSection
+	// terms of each block, the credits of the course are split between them.
+	terms []models.TermSet
+}
+
+// scheduleSearch is a backtracking search over the section blocks of every course.
+type scheduleSearch struct {
+	helper  models.CourseHelper
+	courses []courseCandidates
+	// chosen holds the block chosen for each course, by position.
+	chosen [][]models.CourseSection
+	// placed holds the sections of every chosen block.
+	placed []models.CourseSection
+	// credits of the chosen blocks, in total and by term.
+	credits     float64
+	termCredits termCredits
+	limits      creditLimits
+	// include is the number of optional courses every schedule must have, included is the number chosen.
+	include  int
+	included int
+	// optionalAfter holds the number of optional courses after each depth.
+	optionalAfter []int
+	// optional holds the name of each optional course, by position.
+	optional []string
+	// found is true once a schedule was yielded.
+	found bool
+	yield func(models.Schedule) bool
+}
+
+func newScheduleSearch(helper models.CourseHelper, courses []courseCandidates, yield func(models.Schedule) bool) *scheduleSearch {
+	// Courses with the fewest choices go first so conflicts are found as early as possible.
+	sort.SliceStable(courses, func(i, j int) bool {
+		return len(courses[i].blocks) < len(courses[j].blocks)
+	})
+	optionalAfter := make([]int, len(courses))
+	optional := make([]string, len(courses))
+	for i := len(courses) - 1; i >= 0; i-- {
+		if i+1 < len(courses) {
+			optionalAfter[i] = optionalAfter[i+1]
+			if courses[i+1].optional {
+				optionalAfter[i]++
+			}
+		}
+		if courses[i].optional {
+			optional[courses[i].position] = courses[i].course
+		}
+	}
+	return &scheduleSearch{
+		helper:        helper,
+		courses:       courses,
+		chosen:        make([][]models.CourseSection, len(courses)),
+		optionalAfter: optionalAfter,
+		optional:      optional,
+		yield:         yield,
 	}
-	return newSchedules
 }
 
-func (sc *DefaultScheduleCreator) addSections(schedules []models.Schedule, sections []models.CourseSection) []models.Schedule {
-	if len(schedules) == 0 {
-		for _, section := range sections {
-			sections := []models.CourseSection{section}
-			schedules = append(schedules, models.Schedule{
-				Courses: sections,
-			})
+// run chooses a block for the course at depth and every course after it, or leaves it out if it's optional.
+// Only schedules with exactly include optional courses are yielded. Returns false if the search was stopped.
+func (s *scheduleSearch) run(depth int) bool {
+	if depth == len(s.courses) {
+		if len(s.placed) == 0 || s.included != s.include || !s.limits.reached(s.termCredits) {
+			return true
 		}
-		return schedules
+		s.found = true
+		return s.yield(s.schedule())
 	}
 
-	newSchedules := []models.Schedule{}
-	for _, schedule := range schedules {
-		for _, section := range sections {
-			newSchedule, added := sc.addSection(schedule, section)
-			if added {
-				newSchedules = append(newSchedules, newSchedule)
+	course := s.courses[depth]
+	if course.optional && s.included == s.include {
+		// Enough optional courses were chosen already.
+		s.chosen[course.position] = nil
+		return s.run(depth + 1)
+	}
+	for i, block := range course.blocks {
+		if s.conflicts(block) {
+			continue
+		}
+		credits := s.termCredits.plus(course.credits, course.terms[i])
+		if s.limits.exceeded(credits) {
+			continue
+		}
+		previous := s.termCredits
+		s.chosen[course.position] = block
+		s.placed = append(s.placed, block...)
+		s.credits += course.credits
+		s.termCredits = credits
+		if course.optional {
+			s.included++
+		}
+		ok := s.run(depth + 1)
+		if course.optional {
+			s.included--
+		}
+		s.placed = s.placed[:len(s.placed)-len(block)]
+		s.credits -= course.credits
+		s.termCredits = previous
+		if !ok {
+			return false
+		}
+	}
+	// Leave the optional course out, unless the courses after it are too few to include enough.
+	if course.optional && s.included+s.optionalAfter[depth] >= s.include {
+		s.chosen[course.position] = nil
+		return s.run(depth + 1)
+	}
+	return true
+}
+
+func (s *scheduleSearch) conflicts(block []models.CourseSection) bool {
+	for _, section := range block {
+		for _, placed := range s.placed {
+			if s.helper.SectionsConflict(section, placed) {
+				return true
 			}
 		}
 	}
-	return newSchedules
+	return false
 }
 
-// addSection returns the new schedule if all sections can be added, otherwise returns the old schedule.
-func (sc *DefaultScheduleCreator) addSection(schedule models.Schedule, sections ...models.CourseSection) (models.Schedule, bool) {
-	newSchedule := schedule
-	for _, section := range sections {
-		newSchedule.Courses = append(newSchedule.Courses, section)
-		if sc.helper.ConflictInSchedule(newSchedule) {
-			return schedule, false
+// schedule returns the chosen blocks as a schedule in the order the courses were requested.
+func (s *scheduleSearch) schedule() models.Schedule {
+	sections := make([]models.CourseSection, 0, len(s.placed))
+	var dropped []string
+	for i, block := range s.chosen {
+		if len(block) == 0 && s.optional[i] != "" {
+			dropped = append(dropped, s.optional[i])
 		}
+		sections = append(sections, block...)
+	}
+	return models.Schedule{
+		Courses:     sections,
+		Dropped:     dropped,
+		Credits:     s.credits,
+		TermCredits: s.limits.byTerm(s.termCredits),
+	}
+}
+
+// CourseOfSection returns the course name of a section name. e.g. 'CPSC 221 101' -> 'CPSC 221'
+func CourseOfSection(section string) string {
+	if i := strings.LastIndex(section, " "); i != -1 {
+		return section[:i]
 	}
-	return newSchedule, true
+	return section
 }
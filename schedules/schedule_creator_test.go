@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/smart-cs/scheduler-backend/database"
+	"github.com/smart-cs/scheduler-backend/models"
 	"github.com/smart-cs/scheduler-backend/schedules"
 	"github.com/stretchr/testify/assert"
 )
@@ -12,6 +13,20 @@ func setupScheduleCreatorTests() {
 	database.LoadLocalDatabase("../database/test-coursedb.json")
 }
 
+// newScheduleCreator returns a ScheduleCreator of the current database, failing the test if there's none.
+func newScheduleCreator(t *testing.T) schedules.ScheduleCreator {
+	sc, err := schedules.NewScheduleCreator()
+	assert.NoError(t, err)
+	return sc
+}
+
+// courseCatalog returns the Catalog of the current database, failing the test if there's none.
+func courseCatalog(t *testing.T) *database.Catalog {
+	catalog, err := database.CourseCatalog()
+	assert.NoError(t, err)
+	return catalog
+}
+
 type scheduleCreatorTestTable struct {
 	courses         []string
 	term            string
@@ -29,6 +44,8 @@ var defaultTestTables = []scheduleCreatorTestTable{
 	{[]string{"MATH 335"}, "1-2", 2, 1},
 	{[]string{"MATH 220", "MATH 253"}, "1-2", 54, 2},
 	{[]string{"MATH 220", "MATH 335"}, "1-2", 18, 2},
+	{[]string{"PCTH 300"}, "1-2", 1, 1}, // Year-long section.
+	{[]string{"PCTH 300"}, "1", 1, 1},
 	{[]string{"MATH 001", "MATH 101", "BIOC 202", "BIOC 203", "BIOC 304"}, "1", 0, 0},
 	{[]string{"MATH 001", "MATH 101", "BIOC 202", "BIOC 203", "BIOC 304"}, "2", 0, 0},
 	// Special cases.
@@ -36,13 +53,16 @@ var defaultTestTables = []scheduleCreatorTestTable{
 	{[]string{"non-existent-course 101"}, "1-2", 0, 0},
 	{[]string{"BIOL 111", "non-existent-course 101"}, "1-2", 2, 1},
 	{[]string{"non-existent-course 101", "BIOL 111"}, "1-2", 2, 1},
+	{[]string{"ANAT 392"}, "1", 1, 1}, // Lecture-Laboratory only.
 }
 
 func assertTables(assert *assert.Assertions, testTables []scheduleCreatorTestTable, selectLabsAndTutorials bool) {
-	sc := schedules.NewScheduleCreator()
+	sc, err := schedules.NewScheduleCreator()
+	assert.NoError(err)
+	helper := models.CourseHelper{}
 	for _, tt := range testTables {
 		options := schedules.ScheduleSelectOptions{
-			Term: tt.term,
+			Term:                   tt.term,
 			SelectLabsAndTutorials: selectLabsAndTutorials,
 		}
 		schedules := sc.Create(tt.courses, options)
@@ -58,6 +78,9 @@ func assertTables(assert *assert.Assertions, testTables []scheduleCreatorTestTab
 				schedule, tt.expCoursesLen, len(schedule.Courses)) {
 				break
 			}
+			if !assert.Falsef(helper.ConflictInSchedule(schedule), "schedule %v has a conflict", schedule) {
+				break
+			}
 		}
 	}
 }
@@ -74,6 +97,7 @@ func TestScheduleCreator_CreateDefault(t *testing.T) {
 		{[]string{"CPEN 221"}, "2", 0, 0},
 		{[]string{"APBI 260", "ASIA 100"}, "1-2", 1, 2},
 		{[]string{"CPSC 221", "CPSC 121"}, "1-2", 29, 2},
+		{[]string{"PCTH 300", "BIOL 200"}, "1-2", 5, 2}, // The year-long lecture clashes with BIOL 200 101 in term 1.
 		{[]string{"MATH 001", "MATH 101", "BIOC 202", "BIOC 203", "BIOC 304"}, "1-2", 40, 5},
 	}...)
 	assertTables(assert.New(t), testTables, false)
@@ -82,14 +106,259 @@ func TestScheduleCreator_CreateDefault(t *testing.T) {
 func TestScheduleCreator_CreateWithLabsAndTuts(t *testing.T) {
 	setupScheduleCreatorTests()
 	testTables := append(defaultTestTables, []scheduleCreatorTestTable{
-		{[]string{"APBI 260"}, "1-2", 1, 2},
+		{[]string{"APBI 260"}, "1-2", 1, 3}, // Lecture, Laboratory and Discussion.
 		{[]string{"CPEN 221"}, "1-2", 5, 3},
 		{[]string{"CPSC 110"}, "1-2", 81, 3},
 		{[]string{"CPSC 210"}, "1-2", 99, 2},
 		{[]string{"CPSC 221"}, "1-2", 72, 2},
 		{[]string{"CPSC 221", "CPSC 121"}, "1-2", 64345, 5},
 		{[]string{"APBI 260", "ASIA 100"}, "1-2", 0, 0},
-		{[]string{"MATH 001", "MATH 101", "BIOC 202", "BIOC 203", "BIOC 304"}, "1-2", 102, 6},
+		{[]string{"MATH 001", "MATH 101", "BIOC 202", "BIOC 203", "BIOC 304"}, "1-2", 264, 7}, // MATH 101 has Discussions.
 	}...)
 	assertTables(assert.New(t), testTables, true)
 }
+
+func TestScheduleCreator_ForEach(t *testing.T) {
+	setupScheduleCreatorTests()
+	assert := assert.New(t)
+	sc := newScheduleCreator(t)
+	courses := []string{"CPSC 221", "CPSC 121"}
+	options := schedules.ScheduleSelectOptions{
+		Term:                   "1-2",
+		SelectLabsAndTutorials: true,
+	}
+
+	t.Log("ForEach should stop as soon as the callback returns false")
+	var found []models.Schedule
+	sc.ForEach(courses, options, func(schedule models.Schedule) bool {
+		found = append(found, schedule)
+		return len(found) < 10
+	})
+	assert.Len(found, 10)
+
+	t.Log("ForEach should yield schedules in the same order as Create")
+	all := sc.Create(courses, options)
+	assert.Equal(all[:10], found)
+
+	t.Log("schedules should list the courses in the requested order")
+	for _, schedule := range found {
+		assert.Contains(schedule.Courses[0].Name, "CPSC 221")
+		assert.Contains(schedule.Courses[len(schedule.Courses)-1].Name, "CPSC 121")
+	}
+}
+
+func TestScheduleCreator_Preferences(t *testing.T) {
+	setupScheduleCreatorTests()
+	assert := assert.New(t)
+	sc := newScheduleCreator(t)
+	courses := []string{"CPSC 221", "CPSC 121"}
+	options := schedules.ScheduleSelectOptions{
+		Term: "1-2",
+		Preferences: []schedules.WeightedPreference{
+			{Preference: schedules.NoClassesBefore(1000), Weight: 1},
+			{Preference: schedules.DaysOff(models.Friday), Weight: 1},
+		},
+	}
+
+	ranked := sc.Create(courses, options)
+	assert.Len(ranked, 29, "preferences should only change the order of the schedules")
+	for i := 1; i < len(ranked); i++ {
+		assert.True(ranked[i-1].Score >= ranked[i].Score, "schedules should be sorted from the best to the worst")
+	}
+	assert.Equal(schedules.Score(ranked[0], options.Preferences), ranked[0].Score)
+	assert.True(ranked[0].Score > ranked[len(ranked)-1].Score)
+
+	t.Log("only the best schedules are kept, in the order of Rank")
+	unranked := sc.Create(courses, schedules.ScheduleSelectOptions{Term: "1-2"})
+	schedules.Rank(unranked, options.Preferences)
+	best, total := sc.Ranked(courses, options, 5)
+	assert.Equal(29, total)
+	assert.Equal(unranked[:5], best)
+	best, total = sc.Ranked(courses, options, 0)
+	assert.Equal(29, total)
+	assert.Equal(unranked, best)
+	best, total = sc.Ranked([]string{"APSC 210"}, options, 5)
+	assert.Zero(total)
+	assert.Empty(best)
+}
+
+func TestScheduleCreator_BlockedTimes(t *testing.T) {
+	setupScheduleCreatorTests()
+	assert := assert.New(t)
+	sc := newScheduleCreator(t)
+	helper := models.CourseHelper{}
+	blocks := []models.TimeBlock{
+		{Term: "1-2", Day: "Tue Thu", Start: 1300, End: 1700},
+		{Term: "2", Day: "Mon", Start: 800, End: 1200},
+	}
+	options := schedules.ScheduleSelectOptions{
+		Term:                   "1-2",
+		SelectLabsAndTutorials: true,
+		BlockedTimes:           blocks,
+	}
+
+	all := sc.Create([]string{"CPSC 221"}, schedules.ScheduleSelectOptions{Term: "1-2", SelectLabsAndTutorials: true})
+	blocked := sc.Create([]string{"CPSC 221"}, options)
+	assert.NotEmpty(blocked)
+	assert.True(len(blocked) < len(all))
+	for _, schedule := range blocked {
+		for _, section := range schedule.Courses {
+			for _, b := range blocks {
+				assert.Falsef(helper.SectionsConflict(section, b.Section()), "%v conflicts with %v", section, b)
+			}
+		}
+	}
+
+	t.Log("blocking every weekday should leave no schedules")
+	options.BlockedTimes = []models.TimeBlock{{Term: "1-2", Day: "Mon Tue Wed Thu Fri", Start: 0, End: 2400}}
+	assert.Empty(sc.Create([]string{"CPSC 221"}, options))
+}
+
+func TestScheduleCreator_ExcludeStatuses(t *testing.T) {
+	setupScheduleCreatorTests()
+	assert := assert.New(t)
+	sc := newScheduleCreator(t)
+
+	t.Log("cancelled sections should be excluded by default")
+	assert.Empty(sc.Create([]string{"ANTH 201A"}, schedules.ScheduleSelectOptions{Term: "1"}))
+	assert.Len(sc.Create([]string{"ANTH 201A"}, schedules.ScheduleSelectOptions{
+		Term:            "1",
+		ExcludeStatuses: []models.SectionStatus{},
+	}), 1)
+
+	options := schedules.ScheduleSelectOptions{
+		Term:            "1",
+		ExcludeStatuses: []models.SectionStatus{models.Full},
+	}
+	result := sc.Create([]string{"CPSC 121"}, options)
+	assert.Len(result, 1)
+	assert.Equal("CPSC 121 102", result[0].Courses[0].Name)
+	assert.Equal(models.Restricted, result[0].Courses[0].Status)
+}
+
+func TestScheduleCreator_PinnedAndExcludedSections(t *testing.T) {
+	setupScheduleCreatorTests()
+	assert := assert.New(t)
+	sc := newScheduleCreator(t)
+	courses := []string{"CPSC 221", "CPSC 121"}
+	options := schedules.ScheduleSelectOptions{
+		Term:                   "1-2",
+		SelectLabsAndTutorials: true,
+		PinnedSections:         []string{"CPSC 121 101", "CPSC 121 L1A"},
+		ExcludedSections:       []string{"CPSC 121 T1A"},
+	}
+	assert.Empty(sc.Validate(courses, options))
+
+	result := sc.Create(courses, options)
+	assert.NotEmpty(result)
+	for _, schedule := range result {
+		var names []string
+		for _, section := range schedule.Courses {
+			names = append(names, section.Name)
+		}
+		assert.Subset(names, options.PinnedSections)
+		assert.NotContains(names, "CPSC 121 T1A")
+	}
+
+	options.PinnedSections = []string{"CPSC 121 999"}
+	assert.NotEmpty(sc.Validate(courses, options))
+	options.PinnedSections = []string{"MATH 100 101"}
+	assert.NotEmpty(sc.Validate(courses, options))
+
+	t.Log("a year-long section can be pinned")
+	options = schedules.ScheduleSelectOptions{Term: "1-2", PinnedSections: []string{"PCTH 300 001"}}
+	assert.Empty(sc.Validate([]string{"PCTH 300"}, options))
+	assert.Len(sc.Create([]string{"PCTH 300"}, options), 1)
+}
+
+func TestScheduleCreator_LinkedSections(t *testing.T) {
+	setupScheduleCreatorTests()
+	assert := assert.New(t)
+	var links database.SectionLinks
+	assert.NoError(database.LoadJSONFile("../database/test-section-links.json", &links))
+	catalog := courseCatalog(t).WithSectionLinks(links)
+	sc := schedules.NewDatastoreScheduleCreator(database.NewCatalogDatastore(catalog))
+
+	options := schedules.ScheduleSelectOptions{
+		Term:                   "1",
+		SelectLabsAndTutorials: true,
+		PinnedSections:         []string{"CPSC 121 103"},
+	}
+	for _, schedule := range sc.Create([]string{"CPSC 121"}, options) {
+		for _, section := range schedule.Courses {
+			assert.NotEqual("CPSC 121 L1A", section.Name, "L1A is only linked to 101")
+			assert.NotEqual("CPSC 121 L1B", section.Name, "L1B is only linked to 101 and 102")
+		}
+	}
+
+	options.PinnedSections = []string{"CPSC 121 103", "CPSC 121 L1A"}
+	assert.Empty(sc.Create([]string{"CPSC 121"}, options))
+	options.PinnedSections = []string{"CPSC 121 101", "CPSC 121 L1A"}
+	assert.NotEmpty(sc.Create([]string{"CPSC 121"}, options))
+
+	t.Log("labs of a term should only be linked to the lectures of the term")
+	for _, schedule := range newScheduleCreator(t).Create([]string{"CPSC 121"}, schedules.ScheduleSelectOptions{Term: "1-2", SelectLabsAndTutorials: true}) {
+		term := schedule.Courses[0].Sessions[0].Term
+		for _, section := range schedule.Courses {
+			assert.Equal(term, section.Sessions[0].Term)
+		}
+	}
+}
+
+func TestScheduleCreator_Credits(t *testing.T) {
+	setupScheduleCreatorTests()
+	assert := assert.New(t)
+	var metadata database.CourseMetadata
+	assert.NoError(database.LoadJSONFile("../database/test-course-metadata.json", &metadata))
+	catalog := courseCatalog(t).WithCourseMetadata(metadata)
+	sc := schedules.NewDatastoreScheduleCreator(database.NewCatalogDatastore(catalog))
+
+	result := sc.Create([]string{"CPSC 221", "MATH 220"}, schedules.ScheduleSelectOptions{Term: "1-2"})
+	assert.NotEmpty(result)
+	for _, schedule := range result {
+		assert.Equal(7.0, schedule.Credits)
+	}
+
+	t.Log("courses without credits count as 0")
+	result = sc.Create([]string{"CPSC 221", "APSC 201"}, schedules.ScheduleSelectOptions{Term: "1-2"})
+	assert.NotEmpty(result)
+	assert.Equal(4.0, result[0].Credits)
+}
+
+func TestScheduleCreator_OptionalCourses(t *testing.T) {
+	setupScheduleCreatorTests()
+	assert := assert.New(t)
+	sc := newScheduleCreator(t)
+
+	t.Log("an optional course conflicting with a required course is dropped")
+	courses := []string{"MATH 220"}
+	options := schedules.ScheduleSelectOptions{
+		Term:            "1",
+		OptionalCourses: []string{"BIOL 111", "CPSC 221"},
+		PinnedSections:  []string{"MATH 220 101", "BIOL 111 101"},
+	}
+	assert.Empty(sc.Validate(courses, options))
+	result := sc.Create(courses, options)
+	assert.NotEmpty(result)
+	for _, schedule := range result {
+		assert.Equal([]string{"BIOL 111"}, schedule.Dropped)
+		var names []string
+		for _, section := range schedule.Courses {
+			names = append(names, section.Name)
+		}
+		assert.Contains(names, "MATH 220 101")
+		assert.NotContains(names, "BIOL 111 101")
+	}
+
+	t.Log("optional courses which fit are never dropped")
+	options.PinnedSections = nil
+	for _, schedule := range sc.Create(courses, options) {
+		assert.Empty(schedule.Dropped)
+	}
+
+	t.Log("there are no schedules if a required course conflicts")
+	assert.Empty(sc.Create([]string{"MATH 220", "BIOL 111"}, schedules.ScheduleSelectOptions{
+		Term:           "1",
+		PinnedSections: []string{"MATH 220 101", "BIOL 111 101"},
+	}))
+}
@@ -0,0 +1,125 @@
+package schedules
+
+import (
+	"fmt"
+
+	"github.com/smart-cs/scheduler-backend/models"
+)
+
+// Codes of ValidationError.
+const (
+	// ErrMissingCourses is the code of an error for a request without courses.
+	ErrMissingCourses = "missing_courses"
+	// ErrTooManyCourses is the code of an error for a request with more than MaxCourses courses.
+	ErrTooManyCourses = "too_many_courses"
+	// ErrUnknownCourse is the code of an error for a course which doesn't exist.
+	ErrUnknownCourse = "unknown_course"
+	// ErrBadTerm is the code of an error for a term other than 1, 2 or 1-2.
+	ErrBadTerm = "bad_term"
+	// ErrUnknownSection is the code of an error for a pinned section which doesn't exist.
+	ErrUnknownSection = "unknown_section"
+	// ErrSectionNotRequested is the code of an error for a pinned section of a course which wasn't requested.
+	ErrSectionNotRequested = "section_not_requested"
+	// ErrBadParameter is the code of an error for a malformed parameter. e.g. lectures_only=maybe
+	ErrBadParameter = "bad_parameter"
+)
+
+// MaxCourses is the most courses schedules can be created for at once.
+const MaxCourses = 10
+
+// ValidationError is a machine readable error in a request for schedules.
+type ValidationError struct {
+	// Code identifies the kind of error. e.g. 'unknown_course'
+	Code string `json:"code"`
+	// Param is the name of the invalid parameter. e.g. 'courses'
+	Param string `json:"param,omitempty"`
+	// Value is the invalid value. e.g. 'CPSC 999'
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+// BadParameter returns a ValidationError for a malformed parameter.
+func BadParameter(param, value, message string) ValidationError {
+	return ValidationError{
+		Code:    ErrBadParameter,
+		Param:   param,
+		Value:   value,
+		Message: param + ": " + message,
+	}
+}
+
+// Validate returns the errors which prevent schedules from being created for the courses with the options.
+func (sc *DefaultScheduleCreator) Validate(courses []string, options ScheduleSelectOptions) []ValidationError {
+	var errs []ValidationError
+	if len(courses) == 0 && len(options.OptionalCourses) == 0 {
+		errs = append(errs, ValidationError{
+			Code:    ErrMissingCourses,
+			Param:   "courses",
+			Message: "at least one course is required",
+		})
+	}
+	if n := len(courses) + len(options.OptionalCourses); n > MaxCourses {
+		errs = append(errs, ValidationError{
+			Code:    ErrTooManyCourses,
+			Param:   "courses",
+			Message: fmt.Sprintf("at most %d courses can be requested, got %d", MaxCourses, n),
+		})
+	}
+	errs = append(errs, sc.unknownCourses("courses", courses)...)
+	errs = append(errs, sc.unknownCourses("optional", options.OptionalCourses)...)
+	if options.Term != "1" && options.Term != "2" && options.Term != "1-2" {
+		errs = append(errs, ValidationError{
+			Code:    ErrBadTerm,
+			Param:   "term",
+			Value:   options.Term,
+			Message: fmt.Sprintf("invalid term %q, expected 1, 2 or 1-2", options.Term),
+		})
+	}
+	for _, section := range options.PinnedSections {
+		if !sc.ds.SectionExists(section) {
+			errs = append(errs, ValidationError{
+				Code:    ErrUnknownSection,
+				Param:   "pinned",
+				Value:   section,
+				Message: fmt.Sprintf("pinned section %q doesn't exist", section),
+			})
+		} else if !models.ContainsName(courses, CourseOfSection(section)) && !models.ContainsName(options.OptionalCourses, CourseOfSection(section)) {
+			errs = append(errs, ValidationError{
+				Code:    ErrSectionNotRequested,
+				Param:   "pinned",
+				Value:   section,
+				Message: fmt.Sprintf("pinned section %q isn't a section of the requested courses", section),
+			})
+		}
+	}
+	if options.MinCredits < 0 {
+		errs = append(errs, BadParameter("min_credits", fmt.Sprint(options.MinCredits), "must be a non-negative number"))
+	}
+	if options.MaxCredits < 0 {
+		errs = append(errs, BadParameter("max_credits", fmt.Sprint(options.MaxCredits), "must be a non-negative number"))
+	}
+	if options.MaxCredits > 0 && options.MinCredits > options.MaxCredits {
+		errs = append(errs, BadParameter("min_credits", fmt.Sprint(options.MinCredits), "must be at most max_credits"))
+	}
+	return errs
+}
+
+// unknownCourses returns an error for every course of the parameter which doesn't exist.
+func (sc *DefaultScheduleCreator) unknownCourses(param string, courses []string) []ValidationError {
+	var errs []ValidationError
+	for _, c := range courses {
+		if !sc.ds.CourseExists(c) {
+			errs = append(errs, ValidationError{
+				Code:    ErrUnknownCourse,
+				Param:   param,
+				Value:   c,
+				Message: fmt.Sprintf("course %q doesn't exist", c),
+			})
+		}
+	}
+	return errs
+}
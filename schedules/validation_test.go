@@ -0,0 +1,58 @@
+package schedules_test
+
+import (
+	"testing"
+
+	"github.com/smart-cs/scheduler-backend/schedules"
+	"github.com/stretchr/testify/assert"
+)
+
+func validationCodes(errs []schedules.ValidationError) []string {
+	var codes []string
+	for _, err := range errs {
+		codes = append(codes, err.Code)
+	}
+	return codes
+}
+
+func TestScheduleCreator_Validate(t *testing.T) {
+	setupScheduleCreatorTests()
+	assert := assert.New(t)
+	sc := newScheduleCreator(t)
+	options := schedules.ScheduleSelectOptions{Term: "1-2"}
+
+	assert.Empty(sc.Validate([]string{"CPSC 121", "CPSC 221"}, options))
+	assert.Equal([]string{schedules.ErrMissingCourses}, validationCodes(sc.Validate(nil, options)))
+
+	errs := sc.Validate([]string{"CPSC 121", "CPSC 999", "cpsc 221"}, options)
+	assert.Equal([]string{schedules.ErrUnknownCourse, schedules.ErrUnknownCourse}, validationCodes(errs))
+	assert.Equal("courses", errs[0].Param)
+	assert.Equal("CPSC 999", errs[0].Value)
+	assert.Equal("cpsc 221", errs[1].Value)
+
+	tooMany := make([]string, schedules.MaxCourses+1)
+	for i := range tooMany {
+		tooMany[i] = "CPSC 121"
+	}
+	assert.Equal([]string{schedules.ErrTooManyCourses}, validationCodes(sc.Validate(tooMany, options)))
+
+	for _, term := range []string{"3", "", "A", "2-1"} {
+		errs = sc.Validate([]string{"CPSC 121"}, schedules.ScheduleSelectOptions{Term: term})
+		assert.Equal([]string{schedules.ErrBadTerm}, validationCodes(errs), term)
+	}
+
+	options.PinnedSections = []string{"CPSC 121 999", "CPSC 221 101", "CPSC 121 101"}
+	errs = sc.Validate([]string{"CPSC 121"}, options)
+	assert.Equal([]string{schedules.ErrUnknownSection, schedules.ErrSectionNotRequested}, validationCodes(errs))
+	assert.Equal("pinned", errs[1].Param)
+	assert.Equal("CPSC 221 101", errs[1].Value)
+}
+
+func TestBadParameter(t *testing.T) {
+	assert := assert.New(t)
+	err := schedules.BadParameter("limit", "-1", "must be at least 0")
+	assert.Equal(schedules.ErrBadParameter, err.Code)
+	assert.Equal("limit", err.Param)
+	assert.Equal("-1", err.Value)
+	assert.EqualError(err, "limit: must be at least 0")
+}
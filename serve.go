@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/smart-cs/scheduler-backend/server"
+)
+
+// serve runs the HTTP server until it fails. Returns 2 if the flags are invalid and 1 if the server can't start.
+func serve(args []string, stdout, stderr io.Writer) int {
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&port, "port", port, "port to listen on, $PORT by default")
+	addr := flags.String("addr", "", "address to bind to, every interface by default. e.g. localhost")
+	catalog := flags.String("catalog", defaultDatabasePath, "path of the course database")
+	static := flags.String("static", "./static/", "directory of the files served on the other paths, e.g. the API docs")
+	logFormat := flags.String("log-format", server.LogText, "format of the request logs: text or json")
+	reload := flags.Duration("reload-interval", time.Minute, "how often to reload the course database if it changed, 0 to never")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if _, err := strconv.Atoi(port); err != nil {
+		fmt.Fprintf(stderr, "port %q is not an integer: %v\n", port, err)
+		return 2
+	}
+
+	if !loadCatalog(*catalog, stderr) {
+		return 1
+	}
+	s, err := server.NewServerWithConfig(server.Config{
+		StaticDir:  *static,
+		LogFormat:  *logFormat,
+		LogOutput:  stdout,
+		AdminToken: os.Getenv("ADMIN_TOKEN"),
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "can't start the server: %v\n", err)
+		return 1
+	}
+	s.ReloadOn(syscall.SIGHUP)
+	if *reload > 0 {
+		s.WatchDatabase(*reload)
+	}
+	s.Run(net.JoinHostPort(*addr, port))
+	return 0
+}
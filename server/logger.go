@@ -0,0 +1,49 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/urfave/negroni"
+)
+
+// requestLogEntry is a request logged by jsonLogger.
+type requestLogEntry struct {
+	Time     string `json:"time"`
+	Status   int    `json:"status"`
+	Duration string `json:"duration"`
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+}
+
+// jsonLogger is a middleware logging every request as a line of JSON.
+type jsonLogger struct {
+	logger *log.Logger
+}
+
+func newJSONLogger(out io.Writer) *jsonLogger {
+	return &jsonLogger{logger: log.New(out, "", 0)}
+}
+
+func (l *jsonLogger) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	start := time.Now()
+	next(rw, r)
+
+	entry := requestLogEntry{
+		Time:     start.Format(time.RFC3339),
+		Status:   rw.(negroni.ResponseWriter).Status(),
+		Duration: time.Since(start).String(),
+		Method:   r.Method,
+		Path:     r.URL.Path,
+	}
+	// Marshal escapes control characters and replaces invalid UTF-8, so a path can't break the line.
+	line, err := json.Marshal(entry)
+	if err != nil {
+		l.logger.Printf("can't log request: %v", err)
+		return
+	}
+	l.logger.Println(string(line))
+}
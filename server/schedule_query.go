@@ -0,0 +1,200 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+
+	"github.com/smart-cs/scheduler-backend/models"
+	"github.com/smart-cs/scheduler-backend/schedules"
+)
+
+// ScheduleQuery is a request for a page of schedules, from the query parameters of GET /schedules,
+// a ScheduleRequest or the flags of the generate command.
+type ScheduleQuery struct {
+	Courses []string
+	Options schedules.ScheduleSelectOptions
+	// Offset is the number of schedules before the page.
+	Offset int
+	// Limit is the most schedules in the page, 0 for all of them.
+	Limit int
+	// Explain asks for a full Diagnosis if there are no schedules.
+	Explain bool
+	// Errors are the malformed parameters of the query, the courses and options are validated by the ScheduleCreator.
+	Errors []schedules.ValidationError
+}
+
+// ParseScheduleQuery parses the query parameters of a request for schedules.
+// Every malformed parameter is reported in the Errors of the query.
+func ParseScheduleQuery(params url.Values) ScheduleQuery {
+	query := ScheduleQuery{
+		Courses: listParam(params, "courses"),
+		Options: schedules.ScheduleSelectOptions{
+			Term:             params.Get("term"),
+			PinnedSections:   listParam(params, "pinned"),
+			ExcludedSections: listParam(params, "excluded"),
+			OptionalCourses:  listParam(params, "optional"),
+		},
+	}
+	if query.Options.Term == "" {
+		query.Options.Term = "1-2"
+	}
+
+	lecturesOnly, err := boolParam(params, "lectures_only", true)
+	if err != nil {
+		query.badParameter("lectures_only", params.Get("lectures_only"), "must be true or false")
+	}
+	query.Options.SelectLabsAndTutorials = !lecturesOnly
+	if query.Explain, err = boolParam(params, "explain", false); err != nil {
+		query.badParameter("explain", params.Get("explain"), "must be true or false")
+	}
+
+	if query.Offset, err = intParam(params, "offset", 0); err != nil || query.Offset < 0 {
+		query.badParameter("offset", params.Get("offset"), "must be a non-negative integer")
+	}
+	if query.Limit, err = intParam(params, "limit", 0); err != nil || query.Limit < 0 {
+		query.badParameter("limit", params.Get("limit"), "must be a non-negative integer")
+	}
+
+	if query.Options.MinCredits, err = floatParam(params, "min_credits", 0); err != nil {
+		query.badParameter("min_credits", params.Get("min_credits"), "must be a number")
+	}
+	if query.Options.MaxCredits, err = floatParam(params, "max_credits", 0); err != nil {
+		query.badParameter("max_credits", params.Get("max_credits"), "must be a number")
+	}
+
+	// Preferences are weighted by <name>_weight or 1 by default.
+	for _, name := range schedules.PreferenceNames {
+		if _, present := params[name]; !present {
+			continue
+		}
+		weight, err := floatParam(params, name+"_weight", 1)
+		if err != nil {
+			query.badParameter(name+"_weight", params.Get(name+"_weight"), "must be a finite non-negative number")
+			continue
+		}
+		query.addPreference(name, name+"_weight", name, params.Get(name), weight)
+	}
+
+	for _, value := range params["blocked"] {
+		blocks, err := models.ParseTimeBlocks(value)
+		if err != nil {
+			query.badParameter("blocked", value, err.Error())
+			continue
+		}
+		query.Options.BlockedTimes = append(query.Options.BlockedTimes, blocks...)
+	}
+	if _, present := params["exclude_status"]; present {
+		value := params.Get("exclude_status")
+		if query.Options.ExcludeStatuses, err = models.ParseSectionStatuses(value); err != nil {
+			query.badParameter("exclude_status", value, err.Error())
+		}
+	}
+	return query
+}
+
+// badParameter reports a malformed parameter of the query.
+func (q *ScheduleQuery) badParameter(param, value, message string) {
+	q.Errors = append(q.Errors, schedules.BadParameter(param, value, message))
+}
+
+// addPreference adds the preference with the given name and argument, like ParsePreference, with a weight.
+// The weight must be a finite non-negative number, preferences without effect are left out. e.g. minimize_gaps=false
+func (q *ScheduleQuery) addPreference(param, weightParam, name, value string, weight float64) {
+	preference, err := schedules.ParsePreference(name, value)
+	if err != nil {
+		q.badParameter(param, value, err.Error())
+		return
+	}
+	if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+		q.badParameter(weightParam, fmt.Sprint(weight), "must be a finite non-negative number")
+		return
+	}
+	if preference == nil {
+		return
+	}
+	q.Options.Preferences = append(q.Options.Preferences, schedules.WeightedPreference{
+		Preference: preference,
+		Weight:     weight,
+	})
+}
+
+// FindSchedules returns the response to a query for schedules with a page of the schedules,
+// or the errors of the query and of validating its courses and options.
+// Explains why there are no schedules if there are none.
+func FindSchedules(creator schedules.ScheduleCreator, query ScheduleQuery) StandardResponse {
+	var errs []schedules.ValidationError
+	errs = append(errs, query.Errors...)
+	errs = append(errs, creator.Validate(query.Courses, query.Options)...)
+	if len(errs) != 0 {
+		return StandardResponse{
+			OK:     false,
+			Status: http.StatusBadRequest,
+			Errors: errs,
+		}
+	}
+
+	var page []models.Schedule
+	count := 0
+	hasMore := false
+	if len(query.Options.Preferences) != 0 {
+		page, count, hasMore = rankedPage(creator, query)
+	} else {
+		// Make schedules into an array of size 0 for JSON serialization
+		page = make([]models.Schedule, 0)
+		creator.ForEach(query.Courses, query.Options, func(schedule models.Schedule) bool {
+			if query.Limit > 0 && count == query.Offset+query.Limit {
+				// Found a schedule after the page, no need to create the rest.
+				hasMore = true
+				return false
+			}
+			if count >= query.Offset {
+				page = append(page, schedule)
+			}
+			count++
+			return true
+		})
+	}
+
+	resp := StandardResponse{
+		OK:      true,
+		Status:  http.StatusOK,
+		Body:    page,
+		HasMore: &hasMore,
+	}
+	if !hasMore || len(query.Options.Preferences) != 0 {
+		// Every schedule is counted to rank them.
+		resp.Total = &count
+	}
+	if count == 0 {
+		var diagnosis schedules.Diagnosis
+		if query.Explain {
+			diagnosis = creator.Explain(query.Courses, query.Options)
+		} else {
+			diagnosis = creator.Diagnose(query.Courses, query.Options)
+		}
+		resp.Diagnosis = &diagnosis
+	}
+	return resp
+}
+
+// rankedPage returns the page of the best schedules of a query with preferences, the number of schedules
+// and if there are schedules after the page. Only the schedules up to the end of the page are held while ranking.
+func rankedPage(creator schedules.ScheduleCreator, query ScheduleQuery) ([]models.Schedule, int, bool) {
+	n := 0
+	if query.Limit > 0 {
+		n = query.Offset + query.Limit + 1
+	}
+	best, total := creator.Ranked(query.Courses, query.Options, n)
+	page := make([]models.Schedule, 0)
+	for i, schedule := range best {
+		if query.Limit > 0 && i == query.Offset+query.Limit {
+			return page, total, true
+		}
+		if i >= query.Offset {
+			page = append(page, schedule)
+		}
+	}
+	return page, total, false
+}
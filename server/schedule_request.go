@@ -0,0 +1,164 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/smart-cs/scheduler-backend/models"
+	"github.com/smart-cs/scheduler-backend/schedules"
+)
+
+// ScheduleRequestVersion is the only version of ScheduleRequest the server understands.
+const ScheduleRequestVersion = 1
+
+// maxScheduleRequestSize is the most bytes a ScheduleRequest can have.
+const maxScheduleRequestSize = 1 << 20
+
+// ScheduleRequest is the JSON body of a POST request for schedules, with the same options as the
+// query parameters of a GET request.
+type ScheduleRequest struct {
+	// Version of the request document, must be ScheduleRequestVersion.
+	Version int             `json:"version"`
+	Courses []CourseRequest `json:"courses"`
+	// Term must be 1, 2, 1-2, defaults to 1-2.
+	Term string `json:"term"`
+	// LecturesOnly leaves labs and tutorials out of the schedules, defaults to true.
+	LecturesOnly *bool `json:"lectures_only"`
+	// Blocked are times where no class can be scheduled.
+	Blocked []models.TimeBlock `json:"blocked"`
+	// ExcludeStatus are the statuses of sections which can't be in a schedule, defaults to Cancelled.
+	ExcludeStatus []models.SectionStatus `json:"exclude_status"`
+	Preferences   []PreferenceRequest    `json:"preferences"`
+	// MinCredits and MaxCredits limit the credits of each term, 0 means no limit.
+	MinCredits float64 `json:"min_credits"`
+	MaxCredits float64 `json:"max_credits"`
+	Offset     int     `json:"offset"`
+	Limit      int     `json:"limit"`
+	// Explain asks for a full Diagnosis if there are no schedules.
+	Explain bool `json:"explain"`
+}
+
+// CourseRequest is a course of a ScheduleRequest with its own options.
+type CourseRequest struct {
+	// Code of the course. e.g. 'CPSC 221'
+	Code string `json:"code"`
+	// Optional courses can be left out of a schedule if they don't fit.
+	Optional bool `json:"optional"`
+	// Pinned are sections of the course which must be in every schedule. e.g. 'CPSC 221 101'
+	Pinned []string `json:"pinned"`
+	// Excluded are sections of the course which can't be in a schedule.
+	Excluded []string `json:"excluded"`
+}
+
+// PreferenceRequest is a preference of a ScheduleRequest, with an argument like the query parameter of the preference.
+type PreferenceRequest struct {
+	// Name is one of schedules.PreferenceNames. e.g. 'days_off'
+	Name string `json:"name"`
+	// Value is the argument of the preference. e.g. 'Fri'
+	Value string `json:"value"`
+	// Weight of the preference, defaults to 1.
+	Weight *float64 `json:"weight"`
+}
+
+// decodeScheduleRequest reads a ScheduleRequest from the body of a request, rejecting unknown fields.
+func decodeScheduleRequest(r *http.Request) (ScheduleRequest, error) {
+	var req ScheduleRequest
+	decoder := json.NewDecoder(io.LimitReader(r.Body, maxScheduleRequestSize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		return req, err
+	}
+	if decoder.More() {
+		return req, fmt.Errorf("unexpected data after the request")
+	}
+	return req, nil
+}
+
+// parseScheduleRequest parses the JSON body of a request for schedules.
+// Every malformed field is reported in the Errors of the query.
+func parseScheduleRequest(r *http.Request) ScheduleQuery {
+	var query ScheduleQuery
+	req, err := decodeScheduleRequest(r)
+	if err != nil {
+		query.badParameter("body", "", err.Error())
+		return query
+	}
+	if req.Version != ScheduleRequestVersion {
+		query.badParameter("version", fmt.Sprint(req.Version), fmt.Sprintf("must be %d", ScheduleRequestVersion))
+		return query
+	}
+
+	query = ScheduleQuery{
+		Options: schedules.ScheduleSelectOptions{
+			Term:                   req.Term,
+			SelectLabsAndTutorials: req.LecturesOnly != nil && !*req.LecturesOnly,
+			ExcludeStatuses:        req.ExcludeStatus,
+			MinCredits:             req.MinCredits,
+			MaxCredits:             req.MaxCredits,
+		},
+		Offset:  req.Offset,
+		Limit:   req.Limit,
+		Explain: req.Explain,
+	}
+	if query.Options.Term == "" {
+		query.Options.Term = "1-2"
+	}
+
+	for i, c := range req.Courses {
+		param := fmt.Sprintf("courses[%d]", i)
+		code := strings.TrimSpace(c.Code)
+		if code == "" {
+			query.badParameter(param+".code", c.Code, "must be a course code")
+			continue
+		}
+		if c.Optional {
+			query.Options.OptionalCourses = append(query.Options.OptionalCourses, code)
+		} else {
+			query.Courses = append(query.Courses, code)
+		}
+		for _, section := range c.Pinned {
+			if schedules.CourseOfSection(section) != code {
+				query.badParameter(param+".pinned", section, "must be a section of "+code)
+			}
+		}
+		for _, section := range c.Excluded {
+			if schedules.CourseOfSection(section) != code {
+				query.badParameter(param+".excluded", section, "must be a section of "+code)
+			}
+		}
+		query.Options.PinnedSections = append(query.Options.PinnedSections, c.Pinned...)
+		query.Options.ExcludedSections = append(query.Options.ExcludedSections, c.Excluded...)
+	}
+
+	for i, block := range req.Blocked {
+		if err := block.Validate(); err != nil {
+			query.badParameter(fmt.Sprintf("blocked[%d]", i), "", err.Error())
+			continue
+		}
+		query.Options.BlockedTimes = append(query.Options.BlockedTimes, block)
+	}
+	for i, status := range req.ExcludeStatus {
+		if status == models.UnknownStatus {
+			query.badParameter(fmt.Sprintf("exclude_status[%d]", i), "", "invalid status")
+		}
+	}
+	for i, p := range req.Preferences {
+		param := fmt.Sprintf("preferences[%d]", i)
+		weight := 1.0
+		if p.Weight != nil {
+			weight = *p.Weight
+		}
+		query.addPreference(param, param+".weight", p.Name, p.Value, weight)
+	}
+
+	if query.Offset < 0 {
+		query.badParameter("offset", fmt.Sprint(query.Offset), "must be a non-negative integer")
+	}
+	if query.Limit < 0 {
+		query.badParameter("limit", fmt.Sprint(query.Limit), "must be a non-negative integer")
+	}
+	return query
+}
@@ -2,11 +2,20 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
 	"net/http"
+	"net/url"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/smart-cs/scheduler-backend/models"
+	"github.com/smart-cs/scheduler-backend/database"
 	"github.com/smart-cs/scheduler-backend/schedules"
 
 	"github.com/gorilla/mux"
@@ -14,13 +23,39 @@ import (
 	"github.com/urfave/negroni"
 )
 
+// Formats of the request logs.
+const (
+	LogText = "text"
+	LogJSON = "json"
+)
+
 const logFormat = "{{.StartTime}} | {{.Status}} | {{.Duration}} | {{.Method}} {{.Path}}\n"
 
+// Config configures a Server.
+type Config struct {
+	// StaticDir is the directory of the files served on the other paths, e.g. the API docs. Defaults to ./static/
+	StaticDir string
+	// LogFormat is the format of the request logs, LogText or LogJSON. Defaults to LogText.
+	LogFormat string
+	// LogOutput is where the requests, the problems of the database and its reloads are logged. Defaults to os.Stdout.
+	LogOutput io.Writer
+	// AdminToken authorizes reloading the database with a POST to /admin/reload, which is disabled if it's empty.
+	AdminToken string
+}
+
 // Server runs the backend server.
 type Server struct {
-	Middleware      *negroni.Negroni
-	ScheduleCreator schedules.ScheduleCreator
-	AutoCompleter   schedules.AutoCompleter
+	Middleware *negroni.Negroni
+	// Popularity counts the courses of every valid request for schedules to rank autocompletions.
+	Popularity *schedules.Popularity
+
+	// state holds the current *State, shared by copies of the Server.
+	state    *atomic.Value
+	swapping *sync.Mutex
+	// adminToken authorizes reloading the database, which is disabled if it's empty.
+	adminToken string
+	// logger logs the problems of the database and its reloads to the log output of the config.
+	logger *log.Logger
 }
 
 // StandardResponse is the default response from the server.
@@ -28,87 +63,232 @@ type StandardResponse struct {
 	OK     bool        `json:"OK"`
 	Status int         `json:"status"`
 	Body   interface{} `json:"body"`
+	// Total is the number of results across all pages, only set when it is known.
+	Total *int `json:"total,omitempty"`
+	// HasMore is set for paginated responses, true if there are results after this page.
+	HasMore *bool `json:"has_more,omitempty"`
+	// Errors are the reasons a request failed, only set when OK is false.
+	Errors []schedules.ValidationError `json:"errors,omitempty"`
+	// Diagnosis explains why a request for schedules has no results.
+	Diagnosis *schedules.Diagnosis `json:"diagnosis,omitempty"`
+}
+
+// NewServer constructs a Server to listen on the given port, answering requests with the current database.Snapshot.
+// The database can be reloaded with a POST to /admin/reload if $ADMIN_TOKEN is set.
+// Returns an error if the database can't be loaded.
+func NewServer() (Server, error) {
+	return NewServerWithConfig(Config{AdminToken: os.Getenv("ADMIN_TOKEN")})
 }
 
-// NewServer constructs a Server to listen on the given port.
-func NewServer() Server {
+// NewServerWithConfig constructs a Server like NewServer configured by the config.
+func NewServerWithConfig(config Config) (Server, error) {
+	if config.StaticDir == "" {
+		config.StaticDir = "./static/"
+	}
+	if config.LogFormat == "" {
+		config.LogFormat = LogText
+	}
+	if config.LogOutput == nil {
+		config.LogOutput = os.Stdout
+	}
+	if config.LogFormat != LogText && config.LogFormat != LogJSON {
+		return Server{}, fmt.Errorf("invalid log format %q, expected %s or %s", config.LogFormat, LogText, LogJSON)
+	}
+	snapshot, err := database.Open()
+	if err != nil {
+		return Server{}, err
+	}
+
+	popularity := schedules.NewPopularity()
 	server := Server{
-		Middleware:      negroni.New(),
-		ScheduleCreator: schedules.NewScheduleCreator(),
-		AutoCompleter:   schedules.NewAutoCompleter(),
+		Middleware: negroni.New(),
+		Popularity: popularity,
+		state:      &atomic.Value{},
+		swapping:   &sync.Mutex{},
+		adminToken: config.AdminToken,
+		logger:     log.New(config.LogOutput, "", log.LstdFlags),
 	}
+	server.logProblems(snapshot)
+	server.state.Store(newState(snapshot, popularity))
 
 	router := mux.NewRouter()
 	router.HandleFunc("/schedules", server.SchedulesHandler).
-		Methods("GET").
-		Queries("courses", "{courses}")
+		Methods("GET")
+	router.HandleFunc("/schedules", server.PostSchedulesHandler).
+		Methods("POST")
 	router.HandleFunc("/autocomplete", server.AutocompleteHandler).
 		Methods("GET").
 		Queries("text", "{text}")
-	router.PathPrefix("/").Handler(http.FileServer(http.Dir("./static/")))
+	router.HandleFunc("/search", server.SearchHandler).
+		Methods("GET").
+		Queries("text", "{text}")
+	router.HandleFunc("/courses/{code}", server.CourseHandler).
+		Methods("GET")
+	router.HandleFunc("/admin/reload", server.ReloadHandler).
+		Methods("POST")
+	router.PathPrefix("/").Handler(http.FileServer(http.Dir(config.StaticDir)))
 
-	logger := negroni.NewLogger()
-	logger.SetDateFormat(time.Stamp)
-	logger.SetFormat(logFormat)
+	var logger negroni.Handler = newJSONLogger(config.LogOutput)
+	if config.LogFormat == LogText {
+		textLogger := negroni.NewLogger()
+		textLogger.ALogger = log.New(config.LogOutput, "[negroni] ", 0)
+		textLogger.SetDateFormat(time.Stamp)
+		textLogger.SetFormat(logFormat)
+		logger = textLogger
+	}
 	cors := cors.New(cors.Options{
 		AllowedOrigins: []string{"*"},
-		AllowedMethods: []string{"GET"},
+		AllowedMethods: []string{"GET", "POST"},
 	})
 	server.Middleware.Use(logger)
 	server.Middleware.Use(cors)
 	server.Middleware.Use(negroni.NewRecovery())
 	server.Middleware.UseHandler(router)
-	return server
+	return server, nil
 }
 
-// Run starts the server on $PORT or 8080 by default.
-func (s *Server) Run() {
-	s.Middleware.Run()
+// Run starts the server on the address, or on $PORT or 8080 by default. e.g. 'localhost:8080'
+func (s *Server) Run(addr ...string) {
+	address := negroni.DefaultAddress
+	if len(addr) > 0 {
+		address = addr[0]
+	} else if port := os.Getenv("PORT"); port != "" {
+		address = ":" + port
+	}
+	s.logger.Printf("listening on %s", address)
+	s.logger.Fatal(http.ListenAndServe(address, s.Middleware))
 }
 
 // SchedulesHandler handles the schedule endpoint
 func (s *Server) SchedulesHandler(w http.ResponseWriter, r *http.Request) {
-	courses := strings.Split(r.URL.Query().Get("courses"), ",")
-	term := r.URL.Query().Get("term")
-	lecturesOnly := r.URL.Query().Get("lectures_only")
-	if term == "" {
-		term = "1-2"
-	}
-	selectOptions := schedules.ScheduleSelectOptions{
-		Term: term,
-		SelectLabsAndTutorials: lecturesOnly == "false",
-	}
+	s.serveSchedules(w, ParseScheduleQuery(r.URL.Query()))
+}
 
-	schedules := s.ScheduleCreator.Create(courses, selectOptions)
-	if schedules == nil {
-		// Make schedules into an array of size 0 for JSON serialization
-		schedules = make([]models.Schedule, 0)
+// PostSchedulesHandler handles the schedule endpoint with a ScheduleRequest in the body
+func (s *Server) PostSchedulesHandler(w http.ResponseWriter, r *http.Request) {
+	s.serveSchedules(w, parseScheduleRequest(r))
+}
+
+// serveSchedules responds with a page of the schedules of a query, or the errors parsing it and validating it.
+func (s *Server) serveSchedules(w http.ResponseWriter, query ScheduleQuery) {
+	resp := FindSchedules(s.State().ScheduleCreator, query)
+	if resp.OK {
+		s.Popularity.Record(query.Courses...)
+		s.Popularity.Record(query.Options.OptionalCourses...)
 	}
-	s.respOK(w, schedules)
+	s.resp(w, resp)
 }
 
 // AutocompleteHandler handles the autocomplete endpoint
 func (s *Server) AutocompleteHandler(w http.ResponseWriter, r *http.Request) {
-	text := r.URL.Query().Get("text")
-	completes := s.AutoCompleter.CoursesWithPrefix(text)
-	if completes == nil {
-		// Make completes into an array of size 0 for JSON serialization
-		completes = make([]string, 0)
+	limit, err := intParam(r.URL.Query(), "limit", 0)
+	if err != nil || limit < 0 {
+		s.respErrors(w, http.StatusBadRequest, []schedules.ValidationError{
+			schedules.BadParameter("limit", r.URL.Query().Get("limit"), "must be a non-negative integer"),
+		})
+		return
+	}
+	s.respOK(w, s.State().AutoCompleter.Complete(r.URL.Query().Get("text"), limit))
+}
+
+// CourseHandler handles the course endpoint
+func (s *Server) CourseHandler(w http.ResponseWriter, r *http.Request) {
+	code := mux.Vars(r)["code"]
+	ds := s.State().Datastore
+	course, ok := ds.GetCourse(code)
+	if !ok {
+		course, ok = ds.GetCourse(strings.ToUpper(code))
 	}
-	s.respOK(w, completes)
+	if !ok {
+		s.respErrors(w, http.StatusNotFound, []schedules.ValidationError{{
+			Code:    schedules.ErrUnknownCourse,
+			Param:   "code",
+			Value:   code,
+			Message: fmt.Sprintf("course %q doesn't exist", code),
+		}})
+		return
+	}
+	s.respOK(w, course)
+}
+
+// SearchHandler handles the search endpoint
+func (s *Server) SearchHandler(w http.ResponseWriter, r *http.Request) {
+	limit, err := intParam(r.URL.Query(), "limit", 0)
+	if err != nil || limit < 0 {
+		s.respErrors(w, http.StatusBadRequest, []schedules.ValidationError{
+			schedules.BadParameter("limit", r.URL.Query().Get("limit"), "must be a non-negative integer"),
+		})
+		return
+	}
+	s.respOK(w, s.State().CourseSearcher.Search(r.URL.Query().Get("text"), limit))
 }
 
 func (s *Server) respOK(w http.ResponseWriter, body interface{}) {
-	r := StandardResponse{
+	s.resp(w, StandardResponse{
 		OK:     true,
 		Status: http.StatusOK,
 		Body:   body,
-	}
+	})
+}
+
+func (s *Server) respErrors(w http.ResponseWriter, status int, errs []schedules.ValidationError) {
+	s.resp(w, StandardResponse{
+		OK:     false,
+		Status: status,
+		Errors: errs,
+	})
+}
 
+func (s *Server) resp(w http.ResponseWriter, r StandardResponse) {
 	j, err := json.Marshal(r)
 	if err != nil {
 		panic("can't marshal JSON")
 	}
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(r.Status)
 	w.Write(j)
 }
+
+// intParam returns the integer query parameter with the given name or def if it's missing.
+func intParam(params url.Values, name string, def int) (int, error) {
+	value := params.Get(name)
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// floatParam returns the number query parameter with the given name or def if it's missing.
+func floatParam(params url.Values, name string, def float64) (float64, error) {
+	value := params.Get(name)
+	if value == "" {
+		return def, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err == nil && (math.IsNaN(f) || math.IsInf(f, 0)) {
+		return 0, fmt.Errorf("%s isn't a finite number", value)
+	}
+	return f, err
+}
+
+// boolParam returns the boolean query parameter with the given name or def if it's missing.
+func boolParam(params url.Values, name string, def bool) (bool, error) {
+	value := params.Get(name)
+	if value == "" {
+		return def, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// listParam returns the comma separated values of a query parameter, which can be repeated.
+func listParam(params url.Values, name string) []string {
+	var list []string
+	for _, value := range params[name] {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
@@ -1,39 +1,558 @@
 package server_test
 
 import (
+	"bytes"
 	"encoding/json"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/smart-cs/scheduler-backend/database"
+	"github.com/smart-cs/scheduler-backend/models"
+	"github.com/smart-cs/scheduler-backend/schedules"
 	"github.com/smart-cs/scheduler-backend/server"
 	"github.com/stretchr/testify/assert"
 )
 
+// loadTestDatabase loads the test database, failing the test if it can't be loaded.
+func loadTestDatabase(t *testing.T) {
+	if err := database.LoadLocalDatabase("../database/test-coursedb.json"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// loadTestDatabaseWith loads a copy of the test database in the directory with a copy of a test file next to it,
+// named like the database expects. e.g. 'course-metadata.json'
+func loadTestDatabaseWith(t *testing.T, dir, name, testFile string) {
+	data, err := ioutil.ReadFile(testFile)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), data, 0644))
+	setupReloadTests(t, dir)
+}
+
+// newServer returns a Server of the current database, failing the test if it can't be created.
+func newServer(t *testing.T) *server.Server {
+	s, err := server.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &s
+}
+
+// newTestServer returns a Server of the test database.
+func newTestServer(t *testing.T) *server.Server {
+	loadTestDatabase(t)
+	return newServer(t)
+}
+
+// request serves a request to the server, returns the status code and the response with its body decoded into body,
+// or into an interface{} if body is nil.
+func request(t *testing.T, s *server.Server, method, target, reqBody string, body interface{}) (int, server.StandardResponse) {
+	req, err := http.NewRequest(method, target, strings.NewReader(reqBody))
+	assert.NoError(t, err)
+	rr := httptest.NewRecorder()
+	s.Middleware.ServeHTTP(rr, req)
+	var resp server.StandardResponse
+	if body != nil {
+		resp.Body = body
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp), rr.Body.String())
+	return rr.Code, resp
+}
+
+// get is a GET request of the path with the query.
+func get(t *testing.T, s *server.Server, path string, query url.Values, body interface{}) (int, server.StandardResponse) {
+	if len(query) != 0 {
+		path += "?" + query.Encode()
+	}
+	return request(t, s, "GET", path, "", body)
+}
+
 func TestSchedulesHandlerEmptyBody(t *testing.T) {
 	t.Log("hitting schedules endpoint with a course without possible schedules should return an empty body")
 	assert := assert.New(t)
-	database.LoadLocalDatabase("../database/test-coursedb.json")
+	s := newTestServer(t)
+
+	_, actual := get(t, s, "/schedules", url.Values{"courses": {"APSC 210"}}, nil)
+	total := 0
+	hasMore := false
+	expected := server.StandardResponse{
+		OK:      true,
+		Status:  http.StatusOK,
+		Body:    []interface{}{},
+		Total:   &total,
+		HasMore: &hasMore,
+		Diagnosis: &schedules.Diagnosis{
+			Courses: []schedules.CourseDiagnosis{{
+				Course:  "APSC 210",
+				Reason:  schedules.ReasonNoSectionsInTerm,
+				Message: "APSC 210 has no sections in term 1-2",
+			}},
+		},
+	}
+	assert.EqualValues(expected, actual)
+}
+
+func TestSchedulesHandlerValidation(t *testing.T) {
+	assert := assert.New(t)
+	s := newTestServer(t)
+
+	status, resp := get(t, s, "/schedules", url.Values{
+		"courses":       {"CPSC 121,CPSC 999"},
+		"term":          {"3"},
+		"lectures_only": {"maybe"},
+		"limit":         {"ten"},
+	}, nil)
+	assert.Equal(http.StatusBadRequest, status)
+	assert.False(resp.OK)
+	assert.Equal(http.StatusBadRequest, resp.Status)
+	assert.Nil(resp.Body)
+	assert.Equal([]schedules.ValidationError{
+		{Code: schedules.ErrBadParameter, Param: "lectures_only", Value: "maybe", Message: "lectures_only: must be true or false"},
+		{Code: schedules.ErrBadParameter, Param: "limit", Value: "ten", Message: "limit: must be a non-negative integer"},
+		{Code: schedules.ErrUnknownCourse, Param: "courses", Value: "CPSC 999", Message: `course "CPSC 999" doesn't exist`},
+		{Code: schedules.ErrBadTerm, Param: "term", Value: "3", Message: `invalid term "3", expected 1, 2 or 1-2`},
+	}, resp.Errors)
+
+	status, resp = get(t, s, "/schedules", url.Values{"courses": {strings.Repeat("CPSC 121,", schedules.MaxCourses+1)}}, nil)
+	assert.Equal(http.StatusBadRequest, status)
+	assert.Equal(schedules.ErrTooManyCourses, resp.Errors[0].Code)
+
+	status, resp = get(t, s, "/schedules", url.Values{"courses": {""}}, nil)
+	assert.Equal(http.StatusBadRequest, status)
+	assert.Equal(schedules.ErrMissingCourses, resp.Errors[0].Code)
+
+	status, resp = get(t, s, "/schedules", url.Values{"courses": {"CPSC 121"}, "explain": {"maybe"}}, nil)
+	assert.Equal(http.StatusBadRequest, status)
+	assert.Equal("explain", resp.Errors[0].Param)
+
+	status, resp = get(t, s, "/schedules", url.Values{"courses": {"CPSC 121, CPSC 221"}, "lectures_only": {"false"}}, nil)
+	assert.Equal(http.StatusOK, status)
+	assert.True(resp.OK)
+	assert.Empty(resp.Errors)
+	assert.Nil(resp.Diagnosis, "there are schedules")
+}
+
+func TestSchedulesHandlerPagination(t *testing.T) {
+	assert := assert.New(t)
+	s := newTestServer(t)
+
+	page := func(limit, offset string) (int, server.StandardResponse) {
+		return get(t, s, "/schedules", url.Values{
+			"courses":       {"CPSC 221"},
+			"lectures_only": {"false"},
+			"limit":         {limit},
+			"offset":        {offset},
+		}, nil)
+	}
+
+	t.Log("CPSC 221 with labs has 72 schedules")
+	status, resp := page("", "")
+	assert.Equal(http.StatusOK, status)
+	assert.Len(resp.Body, 72)
+	assert.Equal(72, *resp.Total)
+	assert.False(*resp.HasMore)
+
+	status, resp = page("10", "0")
+	assert.Equal(http.StatusOK, status)
+	assert.Len(resp.Body, 10)
+	assert.Nil(resp.Total, "total isn't known when the creator stopped early")
+	assert.True(*resp.HasMore)
+	firstPage := resp.Body
+
+	_, resp = page("10", "0")
+	assert.Equal(firstPage, resp.Body, "pages should be deterministic")
+
+	_, resp = page("10", "65")
+	assert.Len(resp.Body, 7)
+	assert.Equal(72, *resp.Total)
+	assert.False(*resp.HasMore)
+
+	_, resp = page("10", "100")
+	assert.Empty(resp.Body)
+	assert.False(*resp.HasMore)
+
+	status, resp = page("-1", "")
+	assert.Equal(http.StatusBadRequest, status)
+	assert.False(resp.OK)
+
+	status, resp = page("", "first")
+	assert.Equal(http.StatusBadRequest, status)
+	assert.False(resp.OK)
+}
+
+func TestSchedulesHandlerPreferences(t *testing.T) {
+	assert := assert.New(t)
+	s := newTestServer(t)
+
+	var result []models.Schedule
+	status, _ := get(t, s, "/schedules", url.Values{
+		"courses":                  {"CPSC 221,CPSC 121"},
+		"no_classes_before":        {"10:00"},
+		"days_off":                 {"Fri"},
+		"days_off_weight":          {"2"},
+		"minimize_gaps":            {"false"},
+		"compact_days":             {"true"},
+		"prefer_afternoons_weight": {"5"},
+	}, &result)
+	assert.Equal(http.StatusOK, status)
+	assert.Len(result, 29)
+	assert.NotZero(result[0].Score)
+	assert.True(result[0].Score >= result[len(result)-1].Score)
+
+	t.Log("pages of ranked schedules are the same as ranking every schedule, and are counted")
+	ranked := url.Values{"courses": {"CPSC 221,CPSC 121"}, "no_classes_before": {"10:00"}, "days_off": {"Fri"}}
+	var all, second []models.Schedule
+	get(t, s, "/schedules", ranked, &all)
+	ranked.Set("offset", "2")
+	ranked.Set("limit", "5")
+	_, resp := get(t, s, "/schedules", ranked, &second)
+	assert.Equal(all[2:7], second)
+	assert.True(*resp.HasMore)
+	if assert.NotNil(resp.Total) {
+		assert.Equal(29, *resp.Total)
+	}
+
+	t.Log("weights adding up past the range of float64 still have a score")
+	status, resp = get(t, s, "/schedules", url.Values{
+		"courses":                  {"CPSC 221"},
+		"term":                     {"1"},
+		"prefer_afternoons":        {"true"},
+		"prefer_afternoons_weight": {"1e308"},
+		"minimize_gaps":            {"true"},
+		"minimize_gaps_weight":     {"1e308"},
+	}, nil)
+	assert.Equal(http.StatusOK, status, "%v", resp.Errors)
+
+	for _, query := range []url.Values{
+		{"courses": {"CPSC 221"}, "no_classes_before": {"early"}},
+		{"courses": {"CPSC 221"}, "days_off": {"Caturday"}},
+		{"courses": {"CPSC 221"}, "compact_days": {"yes please"}},
+		{"courses": {"CPSC 221"}, "compact_days": {"true"}, "compact_days_weight": {"-1"}},
+		{"courses": {"CPSC 221"}, "no_classes_before": {"10:00"}, "no_classes_before_weight": {"Inf"}},
+		{"courses": {"CPSC 221"}, "no_classes_before": {"10:00"}, "no_classes_before_weight": {"NaN"}},
+	} {
+		status, _ := get(t, s, "/schedules", query, nil)
+		assert.Equalf(http.StatusBadRequest, status, "%v should be a bad request", query)
+	}
+}
+
+func TestSchedulesHandlerBlockedTimes(t *testing.T) {
+	assert := assert.New(t)
+	s := newTestServer(t)
+
+	status, all := get(t, s, "/schedules", url.Values{"courses": {"CPSC 121"}}, nil)
+	assert.Equal(http.StatusOK, status)
+
+	status, resp := get(t, s, "/schedules", url.Values{"courses": {"CPSC 121"}, "blocked": {"Tue Thu 11:00-12:00"}}, nil)
+	assert.Equal(http.StatusOK, status)
+	assert.True(*resp.Total < *all.Total)
+
+	status, resp = get(t, s, "/schedules", url.Values{
+		"courses": {"CPSC 121"},
+		"blocked": {`[{"term":"1","day":"Tue","start":1100,"end":1200}]`, "2:Mon 9:00-10:00"},
+	}, nil)
+	assert.Equal(http.StatusOK, status)
+	assert.True(*resp.Total < *all.Total)
+
+	status, resp = get(t, s, "/schedules", url.Values{"courses": {"CPSC 121"}, "blocked": {"Tue 17:00-13:00"}}, nil)
+	assert.Equal(http.StatusBadRequest, status)
+	assert.False(resp.OK)
+}
+
+func TestSchedulesHandlerExcludeStatus(t *testing.T) {
+	assert := assert.New(t)
+	s := newTestServer(t)
+
+	schedulesOf := func(query url.Values) (int, []models.Schedule) {
+		var result []models.Schedule
+		status, _ := get(t, s, "/schedules", query, &result)
+		return status, result
+	}
+
+	status, result := schedulesOf(url.Values{"courses": {"ANTH 201A"}})
+	assert.Equal(http.StatusOK, status)
+	assert.Empty(result)
+
+	status, result = schedulesOf(url.Values{"courses": {"ANTH 201A"}, "exclude_status": {""}})
+	assert.Equal(http.StatusOK, status)
+	assert.Len(result, 1)
+	assert.Equal(models.Cancelled, result[0].Courses[0].Status)
+
+	status, result = schedulesOf(url.Values{"courses": {"CPSC 121"}, "term": {"1"}, "exclude_status": {"Full,Cancelled"}})
+	assert.Equal(http.StatusOK, status)
+	assert.Len(result, 1)
+
+	status, _ = schedulesOf(url.Values{"courses": {"CPSC 121"}, "exclude_status": {"Sold out"}})
+	assert.Equal(http.StatusBadRequest, status)
+}
+
+func TestSchedulesHandlerPinnedSections(t *testing.T) {
+	assert := assert.New(t)
+	s := newTestServer(t)
 
-	s := server.NewServer()
-	assert.NotNil(s, "a new server shouldn't be nil")
+	status, resp := get(t, s, "/schedules", url.Values{"courses": {"CPSC 121"}, "pinned": {"CPSC 121 201"}}, nil)
+	assert.Equal(http.StatusOK, status)
+	assert.Equal(1, *resp.Total)
+
+	status, resp = get(t, s, "/schedules", url.Values{"courses": {"CPSC 121"}, "term": {"2"}, "excluded": {"CPSC 121 201,CPSC 121 202"}}, nil)
+	assert.Equal(http.StatusOK, status)
+	assert.Equal(1, *resp.Total)
+
+	status, resp = get(t, s, "/schedules", url.Values{"courses": {"CPSC 121"}, "pinned": {"CPSC 121 999"}}, nil)
+	assert.Equal(http.StatusBadRequest, status)
+	assert.Equal(schedules.ErrUnknownSection, resp.Errors[0].Code)
+	assert.Equal("CPSC 121 999", resp.Errors[0].Value)
+}
+
+func TestSchedulesHandlerExplain(t *testing.T) {
+	assert := assert.New(t)
+	s := newTestServer(t)
+	query := url.Values{"courses": {"CPSC 221,MATH 220,BIOL 111"}, "term": {"1"}, "pinned": {"MATH 220 101"}}
+
+	status, resp := get(t, s, "/schedules", query, nil)
+	assert.Equal(http.StatusOK, status)
+	assert.Len(resp.Diagnosis.Courses, 3)
+	assert.Nil(resp.Diagnosis.Core, "the core is only found when explaining")
+
+	query.Set("explain", "true")
+	status, resp = get(t, s, "/schedules", query, nil)
+	assert.Equal(http.StatusOK, status)
+	assert.Len(resp.Diagnosis.Core, 2)
+	assert.Equal("MATH 220", resp.Diagnosis.Core[0].Course)
+	assert.Equal("BIOL 111", resp.Diagnosis.Core[1].Course)
+	assert.Equal(schedules.ConstraintPinned, resp.Diagnosis.Eliminations[0].Constraint)
+}
 
-	req, err := http.NewRequest("GET", "/schedules", strings.NewReader(url.Values{"courses": {"APSC 210"}}.Encode()))
-	assert.Nil(err, err)
+func TestAutocompleteHandler(t *testing.T) {
+	assert := assert.New(t)
+	s := newTestServer(t)
+
+	complete := func(query url.Values) (int, []schedules.Completion) {
+		var completions []schedules.Completion
+		status, _ := get(t, s, "/autocomplete", query, &completions)
+		return status, completions
+	}
+	codes := func(completions []schedules.Completion) []string {
+		var codes []string
+		for _, c := range completions {
+			codes = append(codes, c.Code)
+		}
+		return codes
+	}
+
+	status, completions := complete(url.Values{"text": {"cpsc 12"}})
+	assert.Equal(http.StatusOK, status)
+	assert.Equal([]schedules.Completion{
+		{Code: "CPSC 121", Department: "CPSC", Number: "121", Terms: []string{"1", "2"}},
+	}, completions)
+
+	_, completions = complete(url.Values{"text": {"CPSC"}, "limit": {"2"}})
+	assert.Equal([]string{"CPSC 100", "CPSC 103"}, codes(completions))
+
+	t.Log("courses requested for schedules are ranked first")
+	get(t, s, "/schedules", url.Values{"courses": {"CPSC 221"}}, nil)
+	_, completions = complete(url.Values{"text": {"CPSC"}, "limit": {"2"}})
+	assert.Equal([]string{"CPSC 221", "CPSC 100"}, codes(completions))
+
+	t.Log("falls back to searching when no course starts with the text")
+	_, completions = complete(url.Values{"text": {"cpsc221"}})
+	assert.Equal("CPSC 221", completions[0].Code)
+	_, completions = complete(url.Values{"text": {"ZZZZZZZZZZZZ"}})
+	assert.NotNil(completions)
+	assert.Empty(completions)
+
+	status, _ = complete(url.Values{"text": {"CPSC"}, "limit": {"-1"}})
+	assert.Equal(http.StatusBadRequest, status)
+}
+
+func TestSearchHandler(t *testing.T) {
+	assert := assert.New(t)
+	s := newTestServer(t)
+
+	var results []schedules.SearchResult
+	status, _ := get(t, s, "/search", url.Values{"text": {"221"}, "limit": {"2"}}, &results)
+	assert.Equal(http.StatusOK, status)
+	assert.Len(results, 2)
+	assert.Equal(schedules.MatchNumber, results[0].Match)
+
+	status, _ = get(t, s, "/search", url.Values{"text": {"221"}, "limit": {"all"}}, nil)
+	assert.Equal(http.StatusBadRequest, status)
+}
 
+func TestCourseHandler(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "metadata")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	loadTestDatabaseWith(t, dir, "course-metadata.json", "../database/test-course-metadata.json")
+	s := newServer(t)
+
+	var course models.Course
+	status, _ := get(t, s, "/courses/CPSC%20221", nil, &course)
+	assert.Equal(http.StatusOK, status)
+	assert.Equal("Basic Algorithms and Data Structures", course.Title)
+	assert.Equal(4.0, course.Credits)
+	assert.Equal("221", course.Number)
+
+	course = models.Course{}
+	status, _ = get(t, s, "/courses/cpsc%20121", nil, &course)
+	assert.Equal(http.StatusOK, status)
+	assert.Equal("CPSC 121", course.Code)
+
+	status, resp := get(t, s, "/courses/CPSC%20999", nil, nil)
+	assert.Equal(http.StatusNotFound, status)
+	assert.Equal(schedules.ErrUnknownCourse, resp.Errors[0].Code)
+}
+
+func TestSchedulesHandlerCredits(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "credits")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	loadTestDatabaseWith(t, dir, "course-credits.json", "../database/test-course-credits.json")
+	s := newServer(t)
+
+	var result []models.Schedule
+	status, _ := get(t, s, "/schedules", url.Values{
+		"optional":    {"CPSC 221,CPSC 121,MATH 220"},
+		"term":        {"1"},
+		"min_credits": {"7"},
+		"max_credits": {"7.5"},
+	}, &result)
+	assert.Equal(http.StatusOK, status)
+	assert.NotEmpty(result)
+	for _, schedule := range result {
+		assert.Equal(7.0, schedule.Credits)
+		assert.Equal(7.0, schedule.TermCredits["1"])
+		assert.Len(schedule.Dropped, 1)
+	}
+
+	status, resp := get(t, s, "/schedules", url.Values{"courses": {"CPSC 121"}, "min_credits": {"lots"}, "max_credits": {"NaN"}}, nil)
+	assert.Equal(http.StatusBadRequest, status)
+	assert.Len(resp.Errors, 2)
+	assert.Equal("min_credits", resp.Errors[0].Param)
+	assert.Equal("max_credits", resp.Errors[1].Param)
+}
+
+func TestPostSchedulesHandler(t *testing.T) {
+	assert := assert.New(t)
+	s := newTestServer(t)
+
+	post := func(body string) (int, server.StandardResponse, []models.Schedule) {
+		var result []models.Schedule
+		status, resp := request(t, s, "POST", "/schedules", body, &result)
+		return status, resp, result
+	}
+
+	t.Log("the courses and options of the request are used")
+	status, resp, result := post(`{
+		"version": 1,
+		"courses": [
+			{"code": "MATH 220", "pinned": ["MATH 220 101"]},
+			{"code": "BIOL 111", "optional": true, "pinned": ["BIOL 111 101"]}
+		],
+		"term": "1",
+		"blocked": [{"term": "1", "day": "Tue Thu", "start": 1700, "end": 1800}],
+		"preferences": [{"name": "days_off", "value": "Fri", "weight": 2}],
+		"limit": 1
+	}`)
+	assert.Equal(http.StatusOK, status)
+	assert.True(resp.OK)
+	assert.Len(result, 1)
+	assert.Equal("MATH 220 101", result[0].Courses[0].Name)
+	assert.Equal([]string{"BIOL 111"}, result[0].Dropped)
+
+	t.Log("the response is the same as for the query parameters of GET")
+	var got []models.Schedule
+	get(t, s, "/schedules", url.Values{"courses": {"CPSC 221"}, "term": {"1"}}, &got)
+	_, _, posted := post(`{"version": 1, "courses": [{"code": "CPSC 221"}], "term": "1"}`)
+	assert.Equal(got, posted)
+
+	t.Log("unknown fields and versions are rejected")
+	status, resp, _ = post(`{"version": 1, "courses": [{"code": "CPSC 221"}], "lectures": false}`)
+	assert.Equal(http.StatusBadRequest, status)
+	assert.Equal("body", resp.Errors[0].Param)
+	assert.Contains(resp.Errors[0].Message, "lectures")
+	status, resp, _ = post(`{"courses": [{"code": "CPSC 221"}]}`)
+	assert.Equal(http.StatusBadRequest, status)
+	assert.Equal("version", resp.Errors[0].Param)
+	status, resp, _ = post(`{"version": 1} {}`)
+	assert.Equal(http.StatusBadRequest, status)
+	assert.Equal("body", resp.Errors[0].Param)
+
+	t.Log("every invalid field is reported")
+	status, resp, _ = post(`{
+		"version": 1,
+		"courses": [{"code": ""}, {"code": "CPSC 221", "pinned": ["CPSC 121 101"]}, {"code": "CPSC 999"}],
+		"blocked": [{"term": "1", "day": "Mon", "start": 1200, "end": 1100}],
+		"exclude_status": ["Maybe"],
+		"preferences": [{"name": "sleep_in", "value": "true"}, {"name": "days_off", "value": "Fri", "weight": -1}],
+		"offset": -1
+	}`)
+	assert.Equal(http.StatusBadRequest, status)
+	var params []string
+	for _, e := range resp.Errors {
+		params = append(params, e.Param)
+	}
+	assert.Equal([]string{
+		"courses[0].code", "courses[1].pinned", "blocked[0]", "exclude_status[0]", "preferences[0]", "preferences[1].weight", "offset",
+		"courses", "pinned",
+	}, params)
+
+	t.Log("weights past the range of float64 aren't numbers")
+	status, resp, _ = post(`{"version": 1, "courses": [{"code": "CPSC 221"}], "preferences": [{"name": "days_off", "value": "Fri", "weight": 1e400}]}`)
+	assert.Equal(http.StatusBadRequest, status)
+	if assert.NotEmpty(resp.Errors) {
+		assert.Equal("body", resp.Errors[0].Param)
+	}
+}
+
+func TestNewServerWithConfig(t *testing.T) {
+	assert := assert.New(t)
+	loadTestDatabase(t)
+	dir, err := ioutil.TempDir("", "static")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "api.txt"), []byte("docs"), 0644))
+
+	var logs bytes.Buffer
+	s, err := server.NewServerWithConfig(server.Config{StaticDir: dir, LogFormat: server.LogJSON, LogOutput: &logs})
+	assert.NoError(err)
+	assert.Contains(logs.String(), "test-coursedb.json has 2 problems", "the problems of the database are logged to the output")
+	logs.Reset()
+	req, err := http.NewRequest("GET", "/api.txt", nil)
+	assert.NoError(err)
 	rr := httptest.NewRecorder()
-	handler := http.HandlerFunc(s.SchedulesHandler)
-	handler.ServeHTTP(rr, req)
+	s.Middleware.ServeHTTP(rr, req)
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Equal("docs", rr.Body.String())
 
-	var actual server.StandardResponse
-	json.Unmarshal(rr.Body.Bytes(), &actual)
-	expected := server.StandardResponse{
-		OK:     true,
-		Status: http.StatusOK,
-		Body:   []interface{}{},
+	t.Log("every request is a line of JSON, whatever its path")
+	for _, path := range []string{"/%01", "/%ff%22"} {
+		req, err := http.NewRequest("GET", path, nil)
+		assert.Nil(err, err)
+		s.Middleware.ServeHTTP(httptest.NewRecorder(), req)
 	}
-	assert.EqualValues(expected, actual)
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if assert.Len(lines, 3) {
+		var paths []string
+		for _, line := range lines {
+			var entry struct {
+				Status int    `json:"status"`
+				Path   string `json:"path"`
+			}
+			assert.NoError(json.Unmarshal([]byte(line), &entry), line)
+			paths = append(paths, entry.Path)
+		}
+		assert.Equal([]string{"/api.txt", "/\x01", "/\ufffd\""}, paths)
+	}
+
+	_, err = server.NewServerWithConfig(server.Config{LogFormat: "xml"})
+	assert.Error(err)
 }
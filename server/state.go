@@ -0,0 +1,144 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/smart-cs/scheduler-backend/database"
+	"github.com/smart-cs/scheduler-backend/schedules"
+)
+
+// Codes of the errors of the reload endpoint.
+const (
+	errUnauthorized = "unauthorized"
+	errReloadFailed = "reload_failed"
+)
+
+// State is what the server answers requests with, built from a database.Snapshot.
+// Reloading the database swaps in a new State, a request reads the State once to use a single snapshot.
+type State struct {
+	Snapshot        *database.Snapshot
+	ScheduleCreator schedules.ScheduleCreator
+	AutoCompleter   schedules.AutoCompleter
+	CourseSearcher  schedules.CourseSearcher
+	Datastore       database.Datastore
+}
+
+// ReloadResult is the body of a response of the reload endpoint.
+type ReloadResult struct {
+	Version uint64    `json:"version"`
+	Courses int       `json:"courses"`
+	ModTime time.Time `json:"mod_time"`
+	// Changed is true if the database files were modified since the previous snapshot.
+	Changed bool `json:"changed"`
+}
+
+// newState builds the State of a snapshot, the autocompleter ranks courses with the popularity.
+func newState(snapshot *database.Snapshot, popularity *schedules.Popularity) *State {
+	ds := database.NewCatalogDatastore(snapshot.Catalog)
+	return &State{
+		Snapshot:        snapshot,
+		ScheduleCreator: schedules.NewDatastoreScheduleCreator(ds),
+		AutoCompleter:   schedules.NewCatalogAutoCompleter(snapshot.Catalog, popularity),
+		CourseSearcher:  schedules.NewCatalogCourseSearcher(snapshot.Catalog),
+		Datastore:       ds,
+	}
+}
+
+// State returns the current State.
+func (s *Server) State() *State {
+	return s.state.Load().(*State)
+}
+
+// Reload loads the database again and swaps in its State. The current State is kept if it can't be loaded.
+func (s *Server) Reload() (*State, error) {
+	snapshot, err := database.Reload()
+	if err != nil {
+		return s.State(), err
+	}
+	return s.use(snapshot), nil
+}
+
+// use swaps in the State of the snapshot, unless the current State is of a later snapshot.
+func (s *Server) use(snapshot *database.Snapshot) *State {
+	s.swapping.Lock()
+	defer s.swapping.Unlock()
+	if current := s.State(); current.Snapshot.Version >= snapshot.Version {
+		return current
+	}
+	s.logProblems(snapshot)
+	state := newState(snapshot, s.Popularity)
+	s.state.Store(state)
+	return state
+}
+
+// logProblems reports the malformed sections of the database.
+func (s *Server) logProblems(snapshot *database.Snapshot) {
+	if len(snapshot.Problems) == 0 {
+		return
+	}
+	s.logger.Printf("%s has %d problems, the sections are loaded as well as they can be:", snapshot.Path, len(snapshot.Problems))
+	for _, p := range snapshot.Problems {
+		s.logger.Printf("  %s", p)
+	}
+}
+
+// WatchDatabase reloads the database when it changes, checking every interval until stop is called.
+func (s *Server) WatchDatabase(interval time.Duration) (stop func()) {
+	return database.Watch(interval, func(snapshot *database.Snapshot, err error) {
+		if err != nil {
+			s.logger.Printf("can't reload the database: %v", err)
+			return
+		}
+		s.use(snapshot)
+		s.logger.Printf("reloaded version %d of the database %s", snapshot.Version, snapshot.Path)
+	})
+}
+
+// ReloadOn reloads the database every time one of the signals is received. e.g. SIGHUP
+func (s *Server) ReloadOn(signals ...os.Signal) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, signals...)
+	go func() {
+		for range c {
+			state, err := s.Reload()
+			if err != nil {
+				s.logger.Printf("can't reload the database: %v", err)
+				continue
+			}
+			s.logger.Printf("reloaded version %d of the database %s", state.Snapshot.Version, state.Snapshot.Path)
+		}
+	}()
+}
+
+// ReloadHandler handles the reload endpoint, it requires the admin token as a bearer token.
+func (s *Server) ReloadHandler(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if s.adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) != 1 {
+		s.respErrors(w, http.StatusUnauthorized, []schedules.ValidationError{{
+			Code:    errUnauthorized,
+			Message: "a valid admin token is required",
+		}})
+		return
+	}
+
+	previous := s.State()
+	state, err := s.Reload()
+	if err != nil {
+		s.respErrors(w, http.StatusInternalServerError, []schedules.ValidationError{{
+			Code:    errReloadFailed,
+			Message: err.Error(),
+		}})
+		return
+	}
+	s.respOK(w, ReloadResult{
+		Version: state.Snapshot.Version,
+		Courses: len(state.Snapshot.Catalog.CourseNames()),
+		ModTime: state.Snapshot.ModTime,
+		Changed: !state.Snapshot.ModTime.Equal(previous.Snapshot.ModTime),
+	})
+}
@@ -0,0 +1,147 @@
+package server_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/smart-cs/scheduler-backend/database"
+	"github.com/smart-cs/scheduler-backend/server"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupReloadTests loads a copy of the test database which can be modified, returns its path.
+func setupReloadTests(t *testing.T, dir string) string {
+	data, err := ioutil.ReadFile("../database/test-coursedb.json")
+	assert.NoError(t, err)
+	path := filepath.Join(dir, "coursedb.json")
+	assert.NoError(t, ioutil.WriteFile(path, data, 0644))
+	if err := database.LoadLocalDatabase(path); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// modify replaces the database with one only having the course and marks it as modified.
+func modify(t *testing.T, path, course string) {
+	db, err := json.Marshal(database.CourseDatabase{"CPSC": {course: {}}})
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(path, db, 0644))
+	later := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(path, later, later))
+}
+
+func TestReloadHandler(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "reload")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	defer database.LoadLocalDatabase("../database/test-coursedb.json")
+	path := setupReloadTests(t, dir)
+
+	os.Setenv("ADMIN_TOKEN", "secret")
+	defer os.Unsetenv("ADMIN_TOKEN")
+	s, err := server.NewServer()
+	assert.NoError(err)
+	before := s.State()
+
+	reload := func(token string) (int, server.ReloadResult) {
+		req, err := http.NewRequest("POST", "/admin/reload", nil)
+		assert.Nil(err, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		s.Middleware.ServeHTTP(rr, req)
+		var resp struct {
+			Body server.ReloadResult `json:"body"`
+		}
+		assert.Nil(json.Unmarshal(rr.Body.Bytes(), &resp))
+		return rr.Code, resp.Body
+	}
+	status := func(path string) int {
+		req, err := http.NewRequest("GET", path, nil)
+		assert.Nil(err, err)
+		rr := httptest.NewRecorder()
+		s.Middleware.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	t.Log("the admin token is required")
+	code, _ := reload("")
+	assert.Equal(http.StatusUnauthorized, code)
+	code, _ = reload("wrong")
+	assert.Equal(http.StatusUnauthorized, code)
+	assert.True(before == s.State())
+
+	t.Log("the courses of the reloaded database are used by every endpoint")
+	modify(t, path, "CPSC 999")
+	code, result := reload("secret")
+	assert.Equal(http.StatusOK, code)
+	assert.True(result.Changed)
+	assert.Equal(1, result.Courses)
+	assert.True(result.Version > before.Snapshot.Version)
+	assert.Equal(http.StatusNotFound, status("/courses/CPSC%20121"))
+	assert.Equal(http.StatusOK, status("/courses/CPSC%20999"))
+	assert.Equal([]string{"CPSC 999"}, s.State().AutoCompleter.CoursesWithPrefix("CPSC"))
+	assert.NotNil(before.Snapshot.Catalog.Course("CPSC 121"), "requests holding the old state keep using it")
+
+	t.Log("reloading an unchanged database reports it")
+	code, result = reload("secret")
+	assert.Equal(http.StatusOK, code)
+	assert.False(result.Changed)
+	assert.Equal(1, result.Courses)
+
+	t.Log("the current state is kept if the database can't be loaded")
+	current := s.State()
+	assert.NoError(ioutil.WriteFile(path, []byte("{"), 0644))
+	code, _ = reload("secret")
+	assert.Equal(http.StatusInternalServerError, code)
+	assert.True(current == s.State())
+
+	t.Log("reloading is disabled without an admin token")
+	os.Unsetenv("ADMIN_TOKEN")
+	s, err = server.NewServer()
+	assert.NoError(err)
+	code, _ = reload("")
+	assert.Equal(http.StatusUnauthorized, code)
+}
+
+func TestServerReloadTriggers(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "reload")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	defer database.LoadLocalDatabase("../database/test-coursedb.json")
+	path := setupReloadTests(t, dir)
+	s := newServer(t)
+
+	waitFor := func(course string) {
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if s.State().Snapshot.Catalog.Course(course) != nil {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Errorf("%s wasn't reloaded", course)
+	}
+
+	t.Log("the database is reloaded when it changes")
+	stop := s.WatchDatabase(10 * time.Millisecond)
+	modify(t, path, "CPSC 998")
+	waitFor("CPSC 998")
+	stop()
+
+	t.Log("the database is reloaded on SIGHUP")
+	s.ReloadOn(syscall.SIGHUP)
+	modify(t, path, "CPSC 997")
+	assert.NoError(syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	waitFor("CPSC 997")
+}
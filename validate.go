@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/smart-cs/scheduler-backend/database"
+)
+
+// validate loads a course database and prints a report of its malformed sections grouped by rule.
+// Returns the exit code: 1 if there are errors, or warnings with -strict, and 2 if the database can't be loaded.
+func validate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+	strict := flags.Bool("strict", false, "exit with 1 if there are warnings too")
+	catalog := flags.String("catalog", defaultDatabasePath, "path of the course database, or the argument")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: validate [-json] [-strict] [-catalog path | path]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	path := *catalog
+	if flags.NArg() > 0 {
+		path = flags.Arg(0)
+	}
+
+	db, err := database.LoadCourseDatabase(path)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	report := database.NewReport(path, db)
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	} else {
+		writeReport(stdout, report)
+	}
+
+	if report.Errors != 0 || (*strict && report.Warnings != 0) {
+		return 1
+	}
+	return 0
+}
+
+// writeReport prints the summary of the report followed by the problems of each rule.
+func writeReport(w io.Writer, report database.Report) {
+	fmt.Fprintf(w, "%s: %d sections, %d errors, %d warnings\n", report.Path, report.Sections, report.Errors, report.Warnings)
+	for _, rule := range report.Rules {
+		fmt.Fprintf(w, "\n%s (%s, %d problems):\n", rule.Rule, rule.Severity, len(rule.Problems))
+		for _, p := range rule.Problems {
+			fmt.Fprintf(w, "  %s: %s\n", p.Section, p.Message)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/smart-cs/scheduler-backend/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	assert := assert.New(t)
+	var stdout, stderr bytes.Buffer
+
+	t.Log("the test database only has warnings")
+	assert.Equal(0, validate([]string{"database/test-coursedb.json"}, &stdout, &stderr))
+	assert.Contains(stdout.String(), "database/test-coursedb.json: 15403 sections, 0 errors, 2 warnings")
+	assert.Contains(stdout.String(), "interval_whitespace (warning, 2 problems):\n  BAEN 506 001: ")
+	stdout.Reset()
+	assert.Equal(1, validate([]string{"-strict", "database/test-coursedb.json"}, &stdout, &stderr))
+
+	t.Log("errors are reported as JSON")
+	dir, err := ioutil.TempDir("", "validate")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "coursedb.json")
+	db := `{"CPSC": {"CPSC 121": {"CPSC 121 101": {
+		"activity": ["Lecture"], "days": ["Mon"], "start_time": ["10:00"], "end_time": ["9:00"], "term": ["1"],
+		"interval": "", "status": ""
+	}}}}`
+	assert.NoError(ioutil.WriteFile(path, []byte(db), 0644))
+	stdout.Reset()
+	assert.Equal(1, validate([]string{"-json", path}, &stdout, &stderr))
+	var report database.Report
+	assert.NoError(json.Unmarshal(stdout.Bytes(), &report))
+	assert.Equal(1, report.Errors)
+	assert.Equal(database.RuleEndBeforeStart, report.Rules[0].Rule)
+
+	t.Log("a database which can't be loaded")
+	assert.NoError(ioutil.WriteFile(path, []byte(`{"CPSC": `), 0644))
+	stdout.Reset()
+	assert.Equal(2, validate([]string{path}, &stdout, &stderr))
+	assert.Contains(stderr.String(), path+": offset")
+	assert.Equal(2, validate([]string{"-nope"}, &stdout, &stderr))
+}